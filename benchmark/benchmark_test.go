@@ -1,9 +1,11 @@
 package benchmark
 
 import (
+	"bytes"
 	stdjson "encoding/json"
 	"fmt"
 	jsonvalue "github.com/Andrew-M-C/go.jsonvalue"
+	djs "github.com/Pencroff/JsonStruct"
 	"github.com/Pencroff/JsonStruct/benchmark/model"
 	"github.com/francoispqt/gojay"
 	gojson "github.com/goccy/go-json"
@@ -203,3 +205,98 @@ func Benchmark_Unmarshal_canada(b *testing.B) {
 	})
 
 }
+
+func Benchmark_Tape(b *testing.B) {
+	for _, name := range []string{"canada", "citm_catalog", "twitter"} {
+		data, err := ReadData("data/" + name + ".json.gz")
+		if err != nil {
+			b.Fatal(err)
+		}
+		fmt.Printf("Data size: %.2f Mb\n", float64(len(data))/1024/1024)
+		var e error
+		b.Run("Simd_"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			var pj *simdjson.ParsedJson
+			for i := 0; i < b.N; i++ {
+				pj, e = simdjson.Parse(data, pj)
+			}
+			if e != nil {
+				b.Fatal(e)
+			}
+			pj.Iter()
+		})
+		b.Run("Tape_"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			var t *djs.Tape
+			for i := 0; i < b.N; i++ {
+				t, e = djs.JStructParseTape(data, nil)
+			}
+			if e != nil {
+				b.Fatal(e)
+			}
+			t.Root()
+		})
+		b.Run("TapeReuse_"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			var t *djs.Tape
+			for i := 0; i < b.N; i++ {
+				t, e = djs.JStructParseTape(data, t)
+			}
+			if e != nil {
+				b.Fatal(e)
+			}
+			t.Root()
+		})
+	}
+}
+
+func Benchmark_Valid(b *testing.B) {
+	for _, name := range []string{"canada", "citm_catalog", "twitter", "code"} {
+		data, err := ReadData("data/" + name + ".json.gz")
+		if err != nil {
+			b.Fatal(err)
+		}
+		fmt.Printf("Data size: %.2f Mb\n", float64(len(data))/1024/1024)
+		ok := false
+		b.Run("Std_"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ok = stdjson.Valid(data)
+			}
+			if !ok {
+				b.Fatal("invalid json")
+			}
+		})
+		b.Run("JStruct_"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ok = djs.Valid(data)
+			}
+			if !ok {
+				b.Fatal("invalid json")
+			}
+		})
+		b.Run("JStructReader_"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			var e error
+			for i := 0; i < b.N; i++ {
+				e = djs.ValidReader(bytes.NewReader(data))
+			}
+			if e != nil {
+				b.Fatal(e)
+			}
+		})
+	}
+}
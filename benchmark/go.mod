@@ -1,21 +1,27 @@
 module github.com/Pencroff/JsonStruct/benchmark
 
-go 1.17
+go 1.23
+
+require (
+	github.com/Andrew-M-C/go.jsonvalue v1.2.1
+	github.com/Pencroff/JsonStruct v0.0.0-00010101000000-000000000000
+	github.com/francoispqt/gojay v1.2.13
+	github.com/goccy/go-json v0.9.8
+	github.com/json-iterator/go v1.1.12
+	github.com/minio/simdjson-go v0.4.2
+	github.com/stretchr/testify v1.8.0
+)
 
 require (
-	github.com/Andrew-M-C/go.jsonvalue v1.2.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/francoispqt/gojay v1.2.13 // indirect
-	github.com/goccy/go-json v0.9.8 // indirect
-	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/klauspost/compress v1.15.7 // indirect
 	github.com/klauspost/cpuid/v2 v2.1.0 // indirect
-	github.com/minio/simdjson-go v0.4.2 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.4.0 // indirect
-	github.com/stretchr/testify v1.8.0 // indirect
 	golang.org/x/sys v0.0.0-20220708085239-5a0f0661e09d // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/Pencroff/JsonStruct => ../
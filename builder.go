@@ -0,0 +1,286 @@
+package JsonStruct
+
+import (
+	"strconv"
+	"strings"
+)
+
+// jStructBuilder assembles JStructOps tree from tokens.
+// Nested nodes created by parent container (SetKey / Push)
+// so the tree consists of the same implementation as root.
+type jStructBuilder struct {
+	root  JStructOps
+	stack []JStructOps // open containers
+	key   string       // key of next value in object
+	alias bool         // strings can point to token data
+	done  bool         // root value completed
+	//=======
+	dup       DuplicateKeyPolicy
+	pos       func() int // position of current token, used by errors
+	path      []string   // keys and indexes of open containers, maintained for DuplicateKeysError and BinaryPaths only
+	ignore    bool       // next value belongs to skipped duplicate key
+	skip      int        // depth of skipped containers
+	collect   bool       // next value collected to array of duplicate key
+	collected map[collectedKey]bool
+	// ParseOptions time detection
+	layouts []string
+	epoch   map[string]EpochUnit
+	binary  map[string]bool // ParseOptions.BinaryPaths
+	// ParseOptions.Spans
+	spans SourceSpans
+	tk    *JStructTokenizerImpl
+	open  []Position // start of open containers
+}
+
+// collectedKey identifies object key already holding array of collected values
+type collectedKey struct {
+	parent JStructOps
+	key    string
+}
+
+func (b *jStructBuilder) add(kind TokenizerKind, level TokenizerLevel, value []byte) error {
+	if b.skip > 0 {
+		b.skipToken(level)
+		return nil
+	}
+	switch level {
+	case LevelKey:
+		if kind == KindLiteral {
+			// unquoted key of relaxed mode
+			b.key = string(value)
+		} else {
+			b.key = unquoteAlias(value, b.alias)
+		}
+		return b.checkKey()
+	case LevelArrayEnd, LevelObjectEnd:
+		b.pop()
+		return nil
+	}
+	if b.ignore {
+		b.ignore = false
+		b.skipToken(level)
+		if level == LevelValueLast {
+			b.pop()
+		}
+		return nil
+	}
+	v, e := b.node()
+	if e != nil {
+		return e
+	}
+	switch level {
+	case LevelArray:
+		v.SetNull()
+		v.AsArray()
+		b.push(v)
+		return nil
+	case LevelObject:
+		v.SetNull()
+		v.AsObject()
+		b.push(v)
+		return nil
+	}
+	e = b.value(kind, value, v)
+	if e != nil {
+		return e
+	}
+	if b.spans != nil {
+		// primitive value takes single line
+		start := b.tk.position(b.tk.Start())
+		end := start
+		end.Offset += len(value)
+		end.Column += len(value)
+		b.spans[v] = Span{Start: start, End: end}
+	}
+	if level == LevelValueLast {
+		b.pop()
+	}
+	if len(b.stack) == 0 {
+		b.done = true
+	}
+	return nil
+}
+
+// value sets primitive value of token to v, strings matching layouts and numbers of epoch keys resolved as time
+func (b *jStructBuilder) value(kind TokenizerKind, value []byte, v JStructOps) error {
+	switch kind {
+	case KindString:
+		if b.binary != nil && b.binary[b.nodePath()] && parseBinary(value, v) {
+			return nil
+		}
+		if len(b.layouts) > 0 && parseTimeLayouts(value, b.layouts, v) {
+			return nil
+		}
+	case KindNumber, KindFloatNumber:
+		l := len(b.stack)
+		if b.epoch == nil || l == 0 || !b.stack[l-1].IsObject() {
+			break
+		}
+		if unit, ok := b.epoch[b.key]; ok && parseEpoch(kind, value, unit, v) {
+			return nil
+		}
+	}
+	return parseTokenValue(kind, value, v, b.alias)
+}
+
+// checkKey applies duplicate key policy to b.key
+func (b *jStructBuilder) checkKey() error {
+	if b.dup == DuplicateKeysLastWins || !b.stack[len(b.stack)-1].HasKey(b.key) {
+		return nil
+	}
+	switch b.dup {
+	case DuplicateKeysFirstWins:
+		b.ignore = true
+	case DuplicateKeysError:
+		pos := -1
+		if b.pos != nil {
+			pos = b.pos()
+		}
+		return DuplicateKeyError{Path: b.keyPath(), Pos: pos}
+	case DuplicateKeysCollectAsArray:
+		b.collect = true
+	}
+	return nil
+}
+
+// skipToken tracks depth of skipped value
+func (b *jStructBuilder) skipToken(level TokenizerLevel) {
+	switch level {
+	case LevelArray, LevelObject:
+		b.skip++
+	case LevelArrayEnd, LevelObjectEnd, LevelValueLast:
+		if b.skip > 0 {
+			b.skip--
+		}
+	}
+}
+
+// node resolves node for the next value
+func (b *jStructBuilder) node() (JStructOps, error) {
+	l := len(b.stack)
+	if l == 0 {
+		return b.root, nil
+	}
+	parent := b.stack[l-1]
+	if parent.IsObject() {
+		if b.collect {
+			b.collect = false
+			return b.collectNode(parent)
+		}
+		e := parent.SetKey(b.key, nil)
+		if e != nil {
+			return nil, e
+		}
+		return parent.GetKey(b.key), nil
+	}
+	return b.pushNode(parent)
+}
+
+// collectNode moves value of duplicate key into array and returns new element of it
+func (b *jStructBuilder) collectNode(parent JStructOps) (JStructOps, error) {
+	k := collectedKey{parent, b.key}
+	if !b.collected[k] {
+		if b.collected == nil {
+			b.collected = map[collectedKey]bool{}
+		}
+		b.collected[k] = true
+		first := parent.RemoveKey(b.key)
+		e := parent.SetKey(b.key, nil)
+		if e != nil {
+			return nil, e
+		}
+		arr := parent.GetKey(b.key)
+		arr.AsArray()
+		e = arr.Push(first)
+		if e != nil {
+			return nil, e
+		}
+	}
+	return b.pushNode(parent.GetKey(b.key))
+}
+
+func (b *jStructBuilder) pushNode(arr JStructOps) (JStructOps, error) {
+	e := arr.Push(nil)
+	if e != nil {
+		return nil, e
+	}
+	return arr.GetIndex(arr.Size() - 1), nil
+}
+
+func (b *jStructBuilder) push(v JStructOps) {
+	if b.dup == DuplicateKeysError || b.binary != nil {
+		b.path = append(b.path, b.segment())
+	}
+	if b.spans != nil {
+		b.open = append(b.open, b.tk.position(b.tk.Start()))
+	}
+	b.stack = append(b.stack, v)
+}
+
+func (b *jStructBuilder) pop() {
+	l := len(b.stack) - 1
+	if b.spans != nil {
+		b.closeSpan(b.stack[l])
+	}
+	b.stack[l] = nil
+	b.stack = b.stack[:l]
+	if b.dup == DuplicateKeysError || b.binary != nil {
+		b.path = b.path[:l]
+	}
+	if l == 0 {
+		b.done = true
+	}
+}
+
+// closeSpan records span of container v closed by current token
+func (b *jStructBuilder) closeSpan(v JStructOps) {
+	idx := b.tk.Start()
+	if b.tk.Level() == LevelValueLast {
+		// closing bracket consumed as delimiter of the last value
+		idx = b.tk.sc.Index()
+	}
+	end := b.tk.position(idx)
+	end.Offset++
+	end.Column++
+	l := len(b.open) - 1
+	b.spans[v] = Span{Start: b.open[l], End: end}
+	b.open = b.open[:l]
+}
+
+// segment returns key or index of the last created node in its parent
+func (b *jStructBuilder) segment() string {
+	l := len(b.stack)
+	if l == 0 {
+		return ""
+	}
+	parent := b.stack[l-1]
+	if parent.IsObject() {
+		return b.key
+	}
+	return strconv.Itoa(parent.Size() - 1)
+}
+
+// keyPath returns JSON Pointer (RFC 6901) of b.key in current object
+func (b *jStructBuilder) keyPath() string {
+	return b.pointer(b.key)
+}
+
+// nodePath returns JSON Pointer of the last created node
+func (b *jStructBuilder) nodePath() string {
+	if len(b.stack) == 0 {
+		return ""
+	}
+	return b.pointer(b.segment())
+}
+
+// pointer returns JSON Pointer of item last in current container
+func (b *jStructBuilder) pointer(last string) string {
+	var sb strings.Builder
+	for _, el := range append(b.path[1:], last) {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(el))
+	}
+	return sb.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
@@ -0,0 +1,96 @@
+package JsonStruct
+
+import (
+	"bytes"
+	"io"
+)
+
+// NewJStructBytesScanner creates scanner over in-memory data.
+// Data is not copied, Bytes() returns sub-slices of b, so b should not be changed during parsing.
+func NewJStructBytesScanner(b []byte) JStructScanner {
+	return &JStructBytesScannerImpl{
+		buf: b,
+		ptr: -1,
+	}
+}
+
+// JStructBytesScannerImpl is JStructScanner backed by byte slice,
+// window moves over the slice without reading and buffer growth
+type JStructBytesScannerImpl struct {
+	buf []byte // scanned data
+
+	start int // start window position
+	ptr   int // current position, equal to index in data
+	//=======
+	finished bool
+}
+
+// Reset reads all data from rd into new buffer (current one can be input data),
+// use ResetBytes to scan data without copy
+func (j *JStructBytesScannerImpl) Reset(rd io.Reader) {
+	buf := bytes.NewBuffer(nil)
+	if rd != nil {
+		buf.ReadFrom(rd)
+	}
+	j.ResetBytes(buf.Bytes())
+}
+
+// ResetBytes starts scanning of b
+func (j *JStructBytesScannerImpl) ResetBytes(b []byte) {
+	j.buf = b
+	j.start = 0
+	j.ptr = -1
+	j.finished = false
+}
+
+// Buffer returns scanned data
+func (j *JStructBytesScannerImpl) Buffer() []byte {
+	return j.buf
+}
+
+// Window returns window of current buffer (start, ptr)
+func (j *JStructBytesScannerImpl) Window() (int, int) {
+	return j.start, j.ptr
+}
+
+// Current returns pointed byte
+func (j *JStructBytesScannerImpl) Current() byte {
+	if j.ptr < 0 || j.ptr < j.start {
+		return 0
+	}
+	return j.buf[j.ptr]
+}
+
+// Index returns current position in data
+func (j *JStructBytesScannerImpl) Index() int {
+	return j.ptr
+}
+
+// Bytes returns data in window (start, ptr), capacity limited to protect the rest of data
+func (j *JStructBytesScannerImpl) Bytes() []byte {
+	idx := j.ptr + 1
+	res := j.buf[j.start:idx:idx]
+	j.start = idx
+	return res
+}
+
+func (j *JStructBytesScannerImpl) Next() error {
+	return j.Scan(1)
+}
+
+func (j *JStructBytesScannerImpl) Scan(n int) error {
+	idx := j.ptr + n
+	if j.finished || idx >= len(j.buf) {
+		j.ptr = len(j.buf) - 1
+		j.finished = true
+		return io.EOF
+	}
+	j.ptr = idx
+	return nil
+}
+
+// FillBuffFrom has nothing to read, all data already in buffer
+func (j *JStructBytesScannerImpl) FillBuffFrom(idx int) (int, error) {
+	j.finished = true
+	return 0, io.EOF
+}
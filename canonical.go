@@ -0,0 +1,160 @@
+package JsonStruct
+
+import (
+	"bufio"
+	"bytes"
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// Canonicalize returns canonical JSON of v defined by RFC 8785 (JCS):
+// keys sorted by UTF-16 code units, numbers formatted same as ECMAScript, minimal string escaping, no whitespaces.
+// Values without canonical form (NaN, Inf, integers not representable as double, invalid UTF-8)
+// fail with NonCanonicalValueError.
+func Canonicalize(v JStructOps) ([]byte, error) {
+	b := bytes.NewBuffer([]byte{})
+	e := JStructCanonicalize(v, b)
+	if e != nil {
+		return nil, e
+	}
+	return b.Bytes(), nil
+}
+
+// JStructCanonicalize writes canonical JSON of v to wr, see Canonicalize
+func JStructCanonicalize(v JStructOps, wr io.Writer) error {
+	c := canonicalEncoder{w: bufio.NewWriter(wr)}
+	e := c.encode(v)
+	if e != nil {
+		return e
+	}
+	return c.w.Flush()
+}
+
+type canonicalEncoder struct {
+	w   *bufio.Writer
+	buf []byte // formatted primitive
+}
+
+func (c *canonicalEncoder) encode(v JStructOps) error {
+	if v == nil {
+		c.w.Write(h.NullTokenData)
+		return nil
+	}
+	c.buf = c.buf[:0]
+	switch v.Type() {
+	case Object:
+		return c.object(v)
+	case Array:
+		c.w.WriteByte(h.OpenBracketCh)
+		for i, l := 0, v.Size(); i < l; i++ {
+			if i > 0 {
+				c.w.WriteByte(h.CommaCh)
+			}
+			e := c.encode(v.GetIndex(i))
+			if e != nil {
+				return e
+			}
+		}
+		c.w.WriteByte(h.CloseBracketCh)
+		return nil
+	case Int:
+		n := v.Int()
+		f := float64(n)
+		if f >= 0x1p63 || int64(f) != n {
+			return NonCanonicalValueError
+		}
+		c.buf = appendCanonicalFloat(c.buf, f)
+	case Uint:
+		n := v.Uint()
+		f := float64(n)
+		if f >= 0x1p64 || uint64(f) != n {
+			return NonCanonicalValueError
+		}
+		c.buf = appendCanonicalFloat(c.buf, f)
+	case Float:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return NonCanonicalValueError
+		}
+		c.buf = appendCanonicalFloat(c.buf, f)
+	case String:
+		s := v.String()
+		if !utf8.ValidString(s) {
+			return NonCanonicalValueError
+		}
+		c.buf = appendEscaped(c.buf, s, EscapeRaw)
+	default:
+		c.buf, _ = appendPrimitive(c.buf, v, SerializeOptions{})
+	}
+	c.w.Write(c.buf)
+	return nil
+}
+
+func (c *canonicalEncoder) object(v JStructOps) error {
+	keys := v.Keys()
+	for _, k := range keys {
+		if !utf8.ValidString(k) {
+			return NonCanonicalValueError
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return lessUTF16(keys[i], keys[j])
+	})
+	c.w.WriteByte(h.OpenBraceCh)
+	for i, k := range keys {
+		if i > 0 {
+			c.w.WriteByte(h.CommaCh)
+		}
+		c.buf = appendEscaped(c.buf[:0], k, EscapeRaw)
+		c.w.Write(c.buf)
+		c.w.WriteByte(h.ColonCh)
+		e := c.encode(v.GetKey(k))
+		if e != nil {
+			return e
+		}
+	}
+	c.w.WriteByte(h.CloseBraceCh)
+	return nil
+}
+
+// appendCanonicalFloat appends finite f same as ECMAScript Number.prototype.toString
+func appendCanonicalFloat(dst []byte, f float64) []byte {
+	if f == 0 {
+		// negative zero written as 0
+		return append(dst, '0')
+	}
+	return appendFloat(dst, f)
+}
+
+// lessUTF16 compares valid UTF-8 strings by their UTF-16 code units
+func lessUTF16(a, b string) bool {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			ua, ub := utf16Unit(ra, true), utf16Unit(rb, true)
+			if ua == ub {
+				// the same high surrogate
+				return utf16Unit(ra, false) < utf16Unit(rb, false)
+			}
+			return ua < ub
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return len(a) < len(b)
+}
+
+// utf16Unit returns the first (high surrogate) or the second code unit of r
+func utf16Unit(r rune, first bool) rune {
+	if r < 0x10000 {
+		return r
+	}
+	r -= 0x10000
+	if first {
+		return 0xD800 + r>>10
+	}
+	return 0xDC00 + r&0x3FF
+}
@@ -0,0 +1,127 @@
+package JsonStruct
+
+import (
+	"context"
+	"io"
+	"sort"
+)
+
+// JStructParseContext parses data from rd with limits of opts and aborts once ctx is done.
+// Context is checked before every read from rd (each scanner buffer refill),
+// cancellation returned as ContextError with position of the first unread byte.
+// In-memory data parsed by JStructParseBytesContext has no reads, context checked between tokens there,
+// so a single huge string or number token is scanned to its end before cancellation noticed.
+func JStructParseContext(ctx context.Context, rd io.Reader, v JStructOps, opts ParseOptions) error {
+	if e := ctx.Err(); e != nil {
+		return ContextError{Err: e}
+	}
+	tc := acquireTokenizer(&contextReader{ctx: ctx, rd: rd}, opts)
+	e := parseTokens(tc, v, false, opts)
+	if e != nil {
+		if ce := ctx.Err(); ce != nil {
+			e = ContextError{Err: ce, Pos: tc.sc.Index() + 1}
+		}
+	}
+	releaseTokenizer(tc)
+	return e
+}
+
+// JStructParseBytesContext parses in-memory data same as JStructParseBytesWithOptions and aborts once ctx is done.
+// Context is checked every contextCheckTokens tokens, cancellation returned as ContextError with position of the next token.
+// Token itself is not interruptible, long string or number scanned completely.
+func JStructParseBytesContext(ctx context.Context, b []byte, v JStructOps, opts ParseOptions) error {
+	if e := ctx.Err(); e != nil {
+		return ContextError{Err: e}
+	}
+	tc := NewJStructTokenizerWithOptions(NewJStructBytesScanner(b), opts).(*JStructTokenizerImpl)
+	e := parseTokensContext(ctx, tc, v, opts.AliasInput, opts)
+	if e != nil {
+		if ce := ctx.Err(); ce != nil {
+			e = ContextError{Err: ce, Pos: tc.sc.Index() + 1}
+		}
+	}
+	return e
+}
+
+// contextReader stops reading from rd once ctx is done
+type contextReader struct {
+	ctx context.Context
+	rd  io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if e := r.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return r.rd.Read(p)
+}
+
+// JStructSerializeContext writes JSON of v to wr same as JStructSerializeWithOptions and aborts once ctx is done.
+// Context is checked between items of objects and arrays and before every write to wr (each writer buffer flush),
+// cancellation returned as ContextError with number of bytes written to wr.
+func JStructSerializeContext(ctx context.Context, v JStructOps, wr io.Writer, opts SerializeOptions) error {
+	cw := &contextWriter{ctx: ctx, wr: wr}
+	w := NewJStructWriterWithOptions(cw, opts).(*JStructWriterImpl)
+	e := serializeContext(ctx, w, v)
+	if e == nil {
+		e = w.Close()
+	}
+	if e != nil {
+		if ce := ctx.Err(); ce != nil {
+			e = ContextError{Err: ce, Pos: cw.n}
+		}
+	}
+	return e
+}
+
+// serializeContext writes v to w item by item, containers with source text written at once
+func serializeContext(ctx context.Context, w *JStructWriterImpl, v JStructOps) error {
+	if e := ctx.Err(); e != nil {
+		return e
+	}
+	if v == nil || !v.IsObject() && !v.IsArray() {
+		return w.Value(v)
+	}
+	if sv, ok := v.(sourceOps); ok && w.opts == (SerializeOptions{}) && sv.source() != nil {
+		return w.Value(v)
+	}
+	if v.IsArray() {
+		e := w.BeginArray()
+		for i, l := 0, v.Size(); i < l && e == nil; i++ {
+			e = serializeContext(ctx, w, v.GetIndex(i))
+		}
+		if e != nil {
+			return e
+		}
+		return w.End()
+	}
+	keys := v.Keys()
+	sort.Strings(keys)
+	e := w.BeginObject()
+	for i := 0; i < len(keys) && e == nil; i++ {
+		e = w.Key(keys[i])
+		if e == nil {
+			e = serializeContext(ctx, w, v.GetKey(keys[i]))
+		}
+	}
+	if e != nil {
+		return e
+	}
+	return w.End()
+}
+
+// contextWriter stops writing to wr once ctx is done, n counts written bytes
+type contextWriter struct {
+	ctx context.Context
+	wr  io.Writer
+	n   int
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if e := w.ctx.Err(); e != nil {
+		return 0, e
+	}
+	n, e := w.wr.Write(p)
+	w.n += n
+	return n, e
+}
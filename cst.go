@@ -0,0 +1,617 @@
+package JsonStruct
+
+import (
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+	"iter"
+	"sort"
+	"time"
+)
+
+// JStructCstNode is JStructOps node of concrete syntax tree, it keeps source text of values
+// and trivia (whitespaces and comments) around them, so serialized tree reproduces
+// the source byte-for-byte except of changed values.
+//
+// Trivia of node in container:
+//
+//	[ <Leading> value <Trailing> , ... ]
+//	{ <Leading> "key" : value <Trailing> , ... }
+//
+// Trivia of root node covers the text before and after root value.
+type JStructCstNode struct {
+	Leading  []byte // whitespaces and comments before value, before key of object member
+	Trailing []byte // whitespaces and comments after value till delimiter
+	//=======
+	v     JsonStruct // primitive value, Object and Array type only for containers
+	raw   []byte     // source text of primitive value, nil if value changed
+	key   []byte     // source text of object key, nil if key added
+	colon []byte     // source text between key and value, nil if key added
+	// container
+	keys   []string // object keys in source order
+	fields map[string]*JStructCstNode
+	items  []*JStructCstNode
+	inner  []byte // trivia before closing bracket of empty container or after trailing comma
+	comma  bool   // trailing comma in relaxed mode
+}
+
+// NewJStructCstNode creates null node without trivia
+func NewJStructCstNode() *JStructCstNode {
+	return &JStructCstNode{}
+}
+
+// Source returns source text of the tree with trivia, changed values serialized in compact form
+func (n *JStructCstNode) Source() []byte {
+	return n.appendNode(nil, "", false)
+}
+
+// WriteTo writes result of Source to w
+func (n *JStructCstNode) WriteTo(w io.Writer) (int64, error) {
+	c, e := w.Write(n.Source())
+	return int64(c), e
+}
+
+func (n *JStructCstNode) appendNode(dst []byte, k string, member bool) []byte {
+	dst = append(dst, n.Leading...)
+	if member {
+		if n.key != nil {
+			dst = append(dst, n.key...)
+		} else {
+			dst = appendQuoted(dst, k)
+		}
+		if n.colon != nil {
+			dst = append(dst, n.colon...)
+		} else {
+			dst = append(dst, h.ColonCh)
+		}
+	}
+	dst = n.appendValue(dst)
+	return append(dst, n.Trailing...)
+}
+
+func (n *JStructCstNode) appendValue(dst []byte) []byte {
+	switch {
+	case n.raw != nil:
+		return append(dst, n.raw...)
+	case n.v.valType == Object:
+		dst = append(dst, h.OpenBraceCh)
+		for i, k := range n.keys {
+			if i > 0 {
+				dst = append(dst, h.CommaCh)
+			}
+			dst = n.fields[k].appendNode(dst, k, true)
+		}
+	case n.v.valType == Array:
+		dst = append(dst, h.OpenBracketCh)
+		for i, el := range n.items {
+			if i > 0 {
+				dst = append(dst, h.CommaCh)
+			}
+			if el == nil {
+				dst = append(dst, h.NullTokenData...)
+				continue
+			}
+			dst = el.appendNode(dst, "", false)
+		}
+	default:
+		// Source can not fail, NaN and Infinity kept as JSON5 literals
+		dst, _ = appendPrimitive(dst, &n.v, SerializeOptions{NonFinite: NonFiniteLiteral})
+		return dst
+	}
+	if n.comma {
+		dst = append(dst, h.CommaCh)
+	}
+	dst = append(dst, n.inner...)
+	if n.v.valType == Object {
+		return append(dst, h.CloseBraceCh)
+	}
+	return append(dst, h.CloseBracketCh)
+}
+
+// clear drops value and children, trivia kept
+func (n *JStructCstNode) clear() {
+	n.raw = nil
+	n.keys = nil
+	n.fields = nil
+	n.items = nil
+	n.inner = nil
+	n.comma = false
+}
+
+//region General operations
+
+func (n *JStructCstNode) Type() Type {
+	return n.v.valType
+}
+
+func (n *JStructCstNode) Value() interface{} {
+	switch n.v.valType {
+	case Object:
+		m := make(map[string]JStructOps, len(n.keys))
+		for k, v := range n.fields {
+			m[k] = v
+		}
+		return m
+	case Array:
+		arr := make([]JStructOps, len(n.items))
+		for i, v := range n.items {
+			arr[i] = cstOps(v)
+		}
+		return arr
+	}
+	return n.v.Value()
+}
+
+func (n *JStructCstNode) Size() int {
+	switch n.v.valType {
+	case Object:
+		return len(n.keys)
+	case Array:
+		return len(n.items)
+	}
+	return n.v.Size()
+}
+
+func (n *JStructCstNode) IsNull() bool {
+	return n.v.IsNull()
+}
+
+func (n *JStructCstNode) SetNull() {
+	n.clear()
+	n.v.SetNull()
+}
+
+func (n *JStructCstNode) IsObject() bool {
+	return n.v.valType == Object
+}
+
+func (n *JStructCstNode) AsObject() {
+	if n.v.valType == Object {
+		return
+	}
+	n.SetNull()
+	n.v.valType = Object
+	n.fields = map[string]*JStructCstNode{}
+}
+
+func (n *JStructCstNode) IsArray() bool {
+	return n.v.valType == Array
+}
+
+func (n *JStructCstNode) AsArray() {
+	if n.v.valType == Array {
+		return
+	}
+	n.SetNull()
+	n.v.valType = Array
+}
+
+//endregion General operations
+
+//region Primitive operations
+
+func (n *JStructCstNode) IsBool() bool {
+	return n.v.IsBool()
+}
+
+func (n *JStructCstNode) SetBool(v bool) {
+	n.clear()
+	n.v.SetBool(v)
+}
+
+func (n *JStructCstNode) Bool() bool {
+	return n.v.Bool()
+}
+
+func (n *JStructCstNode) IsNumber() bool {
+	return n.v.IsNumber()
+}
+
+func (n *JStructCstNode) IsInt() bool {
+	return n.v.IsInt()
+}
+
+func (n *JStructCstNode) SetInt(v int64) {
+	n.clear()
+	n.v.SetInt(v)
+}
+
+func (n *JStructCstNode) Int() int64 {
+	return n.v.Int()
+}
+
+func (n *JStructCstNode) IsUint() bool {
+	return n.v.IsUint()
+}
+
+func (n *JStructCstNode) SetUint(v uint64) {
+	n.clear()
+	n.v.SetUint(v)
+}
+
+func (n *JStructCstNode) Uint() uint64 {
+	return n.v.Uint()
+}
+
+func (n *JStructCstNode) IsFloat() bool {
+	return n.v.IsFloat()
+}
+
+func (n *JStructCstNode) SetFloat(v float64) {
+	n.clear()
+	n.v.SetFloat(v)
+}
+
+func (n *JStructCstNode) Float() float64 {
+	return n.v.Float()
+}
+
+func (n *JStructCstNode) IsString() bool {
+	return n.v.IsString()
+}
+
+func (n *JStructCstNode) SetString(v string) {
+	n.clear()
+	n.v.SetString(v)
+}
+
+func (n *JStructCstNode) String() string {
+	return n.v.String()
+}
+
+func (n *JStructCstNode) IsTime() bool {
+	return n.v.IsTime()
+}
+
+func (n *JStructCstNode) SetTime(v time.Time) {
+	n.clear()
+	n.v.SetTime(v)
+}
+
+func (n *JStructCstNode) Time() time.Time {
+	return n.v.Time()
+}
+
+func (n *JStructCstNode) SetParsedTime(v time.Time, raw string) {
+	n.clear()
+	n.v.SetParsedTime(v, raw)
+}
+
+func (n *JStructCstNode) TimeSource() string {
+	return n.v.TimeSource()
+}
+
+//endregion Primitive operations
+
+//region Strict operations
+
+func (n *JStructCstNode) BoolE() (bool, error) {
+	return boolE(n)
+}
+
+func (n *JStructCstNode) IntE() (int64, error) {
+	return intE(n)
+}
+
+func (n *JStructCstNode) UintE() (uint64, error) {
+	return uintE(n)
+}
+
+func (n *JStructCstNode) FloatE() (float64, error) {
+	return floatE(n)
+}
+
+func (n *JStructCstNode) StringE() (string, error) {
+	return stringE(n)
+}
+
+func (n *JStructCstNode) TimeE() (time.Time, error) {
+	return timeE(n)
+}
+
+//endregion Strict operations
+
+//region Object operations
+
+// SetKey sets value of key, new key appended after existing ones.
+// Node replacing value of existing key takes over its trivia.
+func (n *JStructCstNode) SetKey(key string, v interface{}) error {
+	if n.v.valType != Object {
+		return NotObjectError
+	}
+	old := n.fields[key]
+	el, e := populateCst(v, old)
+	if e != nil {
+		return e
+	}
+	if old == nil {
+		el.key, el.colon = nil, nil
+		n.keys = append(n.keys, key)
+		if l := len(n.keys); l > 1 {
+			el.formatAfter(n.fields[n.keys[l-2]])
+		}
+	}
+	n.fields[key] = el
+	return nil
+}
+
+func (n *JStructCstNode) GetKey(key string) JStructOps {
+	if n.v.valType != Object {
+		return nil
+	}
+	v, ok := n.fields[key]
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+func (n *JStructCstNode) RemoveKey(key string) JStructOps {
+	v, ok := n.fields[key]
+	if !ok {
+		return nil
+	}
+	for i, k := range n.keys {
+		if k == key {
+			n.detach(i)
+			copy(n.keys[i:], n.keys[i+1:])
+			n.keys = n.keys[:len(n.keys)-1]
+			break
+		}
+	}
+	delete(n.fields, key)
+	return v
+}
+
+func (n *JStructCstNode) HasKey(key string) bool {
+	_, ok := n.fields[key]
+	return n.v.valType == Object && ok
+}
+
+// Keys returns keys in source order
+func (n *JStructCstNode) Keys() []string {
+	keys := make([]string, len(n.keys))
+	copy(keys, n.keys)
+	return keys
+}
+
+//endregion Object operations
+
+//region Array operations
+
+func (n *JStructCstNode) Push(v interface{}) error {
+	if n.v.valType != Array {
+		return NotArrayError
+	}
+	el, e := populateCst(v, nil)
+	if e != nil {
+		return e
+	}
+	if l := len(n.items); l > 0 && n.items[l-1] != nil {
+		el.formatAfter(n.items[l-1])
+	}
+	n.items = append(n.items, el)
+	return nil
+}
+
+func (n *JStructCstNode) Pop() JStructOps {
+	l := len(n.items)
+	if n.v.valType != Array || l == 0 {
+		return nil
+	}
+	v := n.items[l-1]
+	n.detach(l - 1)
+	n.items[l-1] = nil
+	n.items = n.items[:l-1]
+	return cstOps(v)
+}
+
+func (n *JStructCstNode) Shift() JStructOps {
+	if n.v.valType != Array || len(n.items) == 0 {
+		return nil
+	}
+	v := n.items[0]
+	n.detach(0)
+	n.items[0] = nil
+	n.items = n.items[1:]
+	return cstOps(v)
+}
+
+// SetIndex sets element i, node replacing existing element takes over its trivia.
+// Elements between the end of array and i stay empty and serialized as null.
+func (n *JStructCstNode) SetIndex(i int, v interface{}) error {
+	if n.v.valType != Array {
+		return NotArrayError
+	}
+	if i < 0 {
+		return IndexOutOfRangeError
+	}
+	var old *JStructCstNode
+	l := len(n.items)
+	if i < l {
+		old = n.items[i]
+	}
+	el, e := populateCst(v, old)
+	if e != nil {
+		return e
+	}
+	if i < l {
+		n.items[i] = el
+		return nil
+	}
+	n.items = append(n.items, make([]*JStructCstNode, i-l)...)
+	return n.Push(el)
+}
+
+func (n *JStructCstNode) GetIndex(i int) JStructOps {
+	if n.v.valType != Array || i < 0 || i >= len(n.items) {
+		return nil
+	}
+	return cstOps(n.items[i])
+}
+
+//endregion Array operations
+
+//region Iteration operations
+
+func (n *JStructCstNode) ForEachKey(fn func(key string, v JStructOps) bool) {
+	if n.v.valType != Object {
+		return
+	}
+	for _, k := range n.keys {
+		if !fn(k, cstOps(n.fields[k])) {
+			return
+		}
+	}
+}
+
+func (n *JStructCstNode) ForEachIndex(fn func(idx int, v JStructOps) bool) {
+	if n.v.valType != Array {
+		return
+	}
+	for i, v := range n.items {
+		if !fn(i, cstOps(v)) {
+			return
+		}
+	}
+}
+
+func (n *JStructCstNode) Entries() iter.Seq2[string, JStructOps] {
+	return n.ForEachKey
+}
+
+func (n *JStructCstNode) All() iter.Seq2[int, JStructOps] {
+	return n.ForEachIndex
+}
+
+//endregion Iteration operations
+
+// region Helper functions
+
+// formatAfter copies indentation of prev to node appended after it,
+// trailing whitespaces of prev (text before closing bracket) moved to node
+func (n *JStructCstNode) formatAfter(prev *JStructCstNode) {
+	n.Leading = lastLineIndent(prev.Leading)
+	n.colon = prev.colon
+	if isWhiteSpace(prev.Trailing) {
+		n.Trailing, prev.Trailing = prev.Trailing, nil
+	}
+}
+
+// detach moves trailing whitespaces of the last child i to its predecessor or container
+func (n *JStructCstNode) detach(i int) {
+	var el *JStructCstNode
+	l := len(n.items)
+	if n.v.valType == Object {
+		el, l = n.fields[n.keys[i]], len(n.keys)
+	} else {
+		el = n.items[i]
+	}
+	if el == nil || i != l-1 || !isWhiteSpace(el.Trailing) {
+		return
+	}
+	switch {
+	case l == 1:
+		if !n.comma {
+			n.inner = append(append([]byte{}, el.Trailing...), n.inner...)
+		}
+		n.comma = false
+	case n.v.valType == Object:
+		prev := n.fields[n.keys[i-1]]
+		if len(prev.Trailing) == 0 {
+			prev.Trailing = el.Trailing
+		}
+	default:
+		prev := n.items[i-1]
+		if prev != nil && len(prev.Trailing) == 0 {
+			prev.Trailing = el.Trailing
+		}
+	}
+}
+
+// populateCst resolves node for v, old node reused for primitive values
+func populateCst(v interface{}, old *JStructCstNode) (*JStructCstNode, error) {
+	var n *JStructCstNode
+	switch data := v.(type) {
+	case *JStructCstNode:
+		n = data
+	case JStructOps:
+		n = toCstNode(data)
+	default:
+		n = old
+		if n == nil {
+			n = &JStructCstNode{}
+		}
+		_, e := n.v.populatePjs(v, &n.v)
+		if e != nil {
+			return nil, e
+		}
+		n.clear()
+		return n, nil
+	}
+	if old != nil && old != n {
+		n.Leading, n.Trailing, n.key, n.colon = old.Leading, old.Trailing, old.key, old.colon
+	}
+	return n, nil
+}
+
+// toCstNode copies v to new tree without trivia, object keys sorted
+func toCstNode(v JStructOps) *JStructCstNode {
+	n := &JStructCstNode{}
+	switch v.Type() {
+	case Object:
+		n.AsObject()
+		keys := v.Keys()
+		sort.Strings(keys)
+		for _, k := range keys {
+			n.keys = append(n.keys, k)
+			n.fields[k] = toCstNode(v.GetKey(k))
+		}
+	case Array:
+		n.AsArray()
+		for i := 0; i < v.Size(); i++ {
+			var el *JStructCstNode
+			if item := v.GetIndex(i); item != nil {
+				el = toCstNode(item)
+			}
+			n.items = append(n.items, el)
+		}
+	default:
+		n.v.populatePjs(v.Value(), &n.v)
+	}
+	return n
+}
+
+// cstOps converts empty element to nil interface
+func cstOps(n *JStructCstNode) JStructOps {
+	if n == nil {
+		return nil
+	}
+	return n
+}
+
+// lastLineIndent returns text of trivia after the last line break if it consists of whitespaces
+func lastLineIndent(b []byte) []byte {
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] == h.NewLineCh {
+			if isWhiteSpace(b[i:]) {
+				return b[i:]
+			}
+			return nil
+		}
+	}
+	if isWhiteSpace(b) {
+		return b
+	}
+	return nil
+}
+
+func isWhiteSpace(b []byte) bool {
+	for _, ch := range b {
+		if !h.SpaceCh[ch] {
+			return false
+		}
+	}
+	return true
+}
+
+// endregion Helper functions
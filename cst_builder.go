@@ -0,0 +1,203 @@
+package JsonStruct
+
+import (
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+	"strconv"
+)
+
+// JStructParseCST parses b into concrete syntax tree keeping trivia of every node,
+// use opts.Relaxed to parse JSONC / JSON5 documents with comments.
+// Repeated keys can not be kept by tree, they fail parsing with DuplicateKeyError.
+func JStructParseCST(b []byte, opts ParseOptions) (*JStructCstNode, error) {
+	src := append([]byte{}, b...)
+	tc := NewJStructTokenizerWithOptions(NewJStructBytesScanner(src), opts).(*JStructTokenizerImpl)
+	cb := cstBuilder{src: src, root: &JStructCstNode{}, keyEnd: -1}
+	for {
+		e := tc.Next()
+		if e == io.EOF {
+			cb.root.Trailing = cb.trivia(src[cb.prev:])
+		}
+		if e == io.EOF && cb.done {
+			return cb.root, nil
+		}
+		if e != nil {
+			return nil, e
+		}
+		e = cb.add(tc.Kind(), tc.Level(), tc.Value(), tc.Start())
+		if e != nil {
+			return nil, e
+		}
+	}
+}
+
+// cstBuilder assembles JStructCstNode tree from tokens and source text between them.
+// Tokenizer consumes delimiters as part of tokens, so builder finds them in the text between tokens
+// instead of relying on token levels.
+type cstBuilder struct {
+	src   []byte
+	root  *JStructCstNode
+	stack []*JStructCstNode // open containers
+	last  *JStructCstNode   // the last completed node
+	prev  int               // end of processed text
+	done  bool              // root value completed
+	comma bool              // comma after the last node of current container
+	// object member
+	key     string
+	keyRaw  []byte
+	keyEnd  int    // end of key, -1 if value of key is not expected
+	leading []byte // trivia before key
+}
+
+func (b *cstBuilder) add(kind TokenizerKind, level TokenizerLevel, value []byte, start int) error {
+	if level == LevelArrayEnd || level == LevelObjectEnd {
+		// closing bracket processed as delimiter in text before the next token
+		return nil
+	}
+	var leading []byte
+	if b.keyEnd < 0 {
+		// text between key and value kept as is by node
+		leading = b.trivia(b.src[b.prev:start])
+	}
+	if level == LevelKey {
+		if kind == KindLiteral {
+			b.key = string(value)
+		} else {
+			b.key = unquote(value)
+		}
+		parent := b.stack[len(b.stack)-1]
+		if parent.HasKey(b.key) {
+			return DuplicateKeyError{Path: b.keyPath(), Pos: start}
+		}
+		b.leading, b.keyRaw = leading, value
+		b.keyEnd = start + len(value)
+		b.prev = b.keyEnd
+		return nil
+	}
+	n := b.node(leading, start)
+	switch level {
+	case LevelArray, LevelObject:
+		if level == LevelArray {
+			n.AsArray()
+		} else {
+			n.AsObject()
+		}
+		b.stack = append(b.stack, n)
+		b.prev = start + 1
+		return nil
+	}
+	e := parseTokenValue(kind, value, n, false)
+	if e != nil {
+		return e
+	}
+	n.raw = value
+	b.last = n
+	b.prev = start + len(value)
+	if len(b.stack) == 0 {
+		b.done = true
+	}
+	return nil
+}
+
+// node creates node of value started at start and adds it to current container
+func (b *cstBuilder) node(leading []byte, start int) *JStructCstNode {
+	l := len(b.stack)
+	if l == 0 {
+		b.root.Leading = leading
+		return b.root
+	}
+	parent := b.stack[l-1]
+	n := &JStructCstNode{}
+	b.comma = false
+	if parent.IsObject() {
+		n.Leading, n.key, n.colon = b.leading, b.keyRaw, b.src[b.keyEnd:start]
+		parent.keys = append(parent.keys, b.key)
+		parent.fields[b.key] = n
+		b.keyEnd = -1
+		return n
+	}
+	n.Leading = leading
+	parent.items = append(parent.items, n)
+	return n
+}
+
+// trivia processes delimiters of text and returns trivia after the last of them
+func (b *cstBuilder) trivia(text []byte) []byte {
+	from := 0
+	for {
+		i := skipTrivia(text, from)
+		if i == len(text) {
+			return text[from:]
+		}
+		b.delimiter(text[i], text[from:i])
+		from = i + 1
+	}
+}
+
+// delimiter assigns trivia before delimiter ch
+func (b *cstBuilder) delimiter(ch byte, trivia []byte) {
+	switch ch {
+	case h.CommaCh:
+		b.last.Trailing = trivia
+		b.comma = true
+	case h.CloseBracketCh, h.CloseBraceCh:
+		l := len(b.stack) - 1
+		c := b.stack[l]
+		if b.comma || c.Size() == 0 {
+			c.comma = b.comma
+			c.inner = trivia
+		} else {
+			b.last.Trailing = trivia
+		}
+		b.stack[l] = nil
+		b.stack = b.stack[:l]
+		b.last = c
+		b.comma = false
+		if l == 0 {
+			b.done = true
+		}
+	}
+}
+
+// keyPath returns JSON Pointer of b.key in current object
+func (b *cstBuilder) keyPath() string {
+	jb := jStructBuilder{path: []string{""}, key: b.key}
+	for i, c := range b.stack[:len(b.stack)-1] {
+		if c.IsArray() {
+			jb.path = append(jb.path, strconv.Itoa(c.Size()-1))
+			continue
+		}
+		for _, k := range c.keys {
+			if c.fields[k] == b.stack[i+1] {
+				jb.path = append(jb.path, k)
+			}
+		}
+	}
+	return jb.keyPath()
+}
+
+// skipTrivia returns index of the first byte of text after whitespaces and comments started at from
+func skipTrivia(text []byte, from int) int {
+	i := from
+	l := len(text)
+	for i < l {
+		ch := text[i]
+		switch {
+		case h.SpaceCh[ch]:
+			i++
+		case ch == h.SlashCh && i+1 < l && text[i+1] == h.SlashCh:
+			for i < l && text[i] != h.NewLineCh {
+				i++
+			}
+		case ch == h.SlashCh && i+1 < l && text[i+1] == h.AsteriskCh:
+			i += 2
+			for i+1 < l && !(text[i] == h.AsteriskCh && text[i+1] == h.SlashCh) {
+				i++
+			}
+			i += 2
+		default:
+			return i
+		}
+	}
+	return l
+}
@@ -2,6 +2,7 @@ package JsonStruct
 
 import (
 	"encoding/json"
+	"iter"
 	"time"
 )
 
@@ -18,6 +19,7 @@ const (
 	Time
 	Object
 	Array
+	Binary
 )
 
 func (t Type) String() string {
@@ -42,6 +44,8 @@ func (t Type) String() string {
 		return "Object"
 	case Array:
 		return "Array"
+	case Binary:
+		return "Binary"
 	}
 }
 
@@ -89,6 +93,33 @@ type PrimitiveOps interface {
 	Time() time.Time
 }
 
+// StrictOps provides accessors failing with TypeMismatchError instead of conversion of other types,
+// numbers converted only within range of requested type, otherwise NumberOverflowError returned
+type StrictOps interface {
+	BoolE() (bool, error)
+	IntE() (int64, error)
+	UintE() (uint64, error)
+	FloatE() (float64, error) // integer numbers converted
+	StringE() (string, error)
+	TimeE() (time.Time, error)
+}
+
+// BinaryOps is implemented by values supporting Binary type.
+// JStructCstNode does not implement it, its binary values available by Value.
+type BinaryOps interface {
+	IsBinary() bool
+	SetBytes([]byte)
+	Bytes() []byte
+}
+
+// TimeSourceOps is implemented by values keeping JSON text of parsed Time to write it back unchanged.
+// SetParsedTime sets time parsed from token raw (quoted string or epoch number),
+// TimeSource returns the token or empty string for values set by SetTime.
+type TimeSourceOps interface {
+	SetParsedTime(t time.Time, raw string)
+	TimeSource() string
+}
+
 type ObjectOps interface {
 	SetKey(string, interface{}) error
 	GetKey(string) JStructOps
@@ -105,6 +136,15 @@ type ArrayOps interface {
 	GetIndex(int) JStructOps
 }
 
+// IterOps iterates containers without copying keys, iteration stops when fn (or range loop body) returns false.
+// Order of object members same as Keys, values of other types have nothing to iterate.
+type IterOps interface {
+	ForEachKey(fn func(key string, v JStructOps) bool)
+	ForEachIndex(fn func(idx int, v JStructOps) bool)
+	Entries() iter.Seq2[string, JStructOps] // object members
+	All() iter.Seq2[int, JStructOps]        // array items
+}
+
 type JStructOps interface {
 	GeneralOps
 	PrimitiveOps
@@ -12,6 +12,23 @@ var UnsupportedTypeError = errors.New("JsonStruct: unsupported value type, resol
 var InvalidHexNumberError = errors.New("JsonStruct: invalid hex number")
 var InvalidEscapeCharacterError = errors.New("JsonStruct: invalid escape character")
 var InvalidCharacterError = errors.New("JsonStruct: invalid character")
+var UnterminatedCommentError = errors.New("JsonStruct: unterminated comment")
+var ClosedPushParserError = errors.New("JsonStruct: write to closed push parser")
+var MaxDepthExceededError = errors.New("JsonStruct: max depth exceeded")
+var MaxBytesExceededError = errors.New("JsonStruct: max bytes exceeded")
+var MaxStringLengthExceededError = errors.New("JsonStruct: max string length exceeded")
+var MaxNumberLengthExceededError = errors.New("JsonStruct: max number length exceeded")
+var MaxKeysPerObjectExceededError = errors.New("JsonStruct: max keys per object exceeded")
+var MaxArrayLengthExceededError = errors.New("JsonStruct: max array length exceeded")
+var ReadOnlyError = errors.New("JsonStruct: read-only value")
+var NonCanonicalValueError = errors.New("JsonStruct: value has no canonical JSON form")
+var NonFiniteFloatError = errors.New("JsonStruct: NaN and Infinity are not valid JSON numbers")
+var NumberOverflowError = errors.New("JsonStruct: number out of range of requested type")
+var WriterStateError = errors.New("JsonStruct: writer call out of order")
+var PathNotFoundError = errors.New("JsonStruct: value not found by path")
+var ConversionError = errors.New("JsonStruct: value not convertible to requested type")
+var UnsupportedOptionError = errors.New("JsonStruct: parse option not supported by parser")
+var TapeSizeExceededError = errors.New("JsonStruct: source exceeds 4GB limit of tape")
 
 type InvalidJsonError struct {
 	Err error
@@ -51,4 +68,66 @@ func (i InvalidJsonTokenPtrError) Error() string {
 	return msg
 }
 
+// LimitExceededError reports ParseOptions limit (Err) exceeded at position Pos
+type LimitExceededError struct {
+	Err error
+	Pos int
+}
+
+func (i LimitExceededError) Error() string {
+	return "JsonStruct: limit exceeded at position " + strconv.FormatInt(int64(i.Pos), 10) + ": " + i.Err.Error()
+}
+
+func (i LimitExceededError) Unwrap() error {
+	return i.Err
+}
+
+// ContextError reports parsing or serialization aborted by context (Err) at position Pos of input or output
+type ContextError struct {
+	Err error
+	Pos int
+}
+
+func (i ContextError) Error() string {
+	return "JsonStruct: aborted by context at position " + strconv.FormatInt(int64(i.Pos), 10) + ": " + i.Err.Error()
+}
+
+func (i ContextError) Unwrap() error {
+	return i.Err
+}
+
+// NDJSONLineError reports invalid value (Err) at line Line of NDJSON input, line numbers start from 1
+type NDJSONLineError struct {
+	Line int
+	Err  error
+}
+
+func (i NDJSONLineError) Error() string {
+	return "JsonStruct: line " + strconv.FormatInt(int64(i.Line), 10) + ": " + i.Err.Error()
+}
+
+func (i NDJSONLineError) Unwrap() error {
+	return i.Err
+}
+
+// DuplicateKeyError reports repeated object key at JSON Pointer Path, Pos is position of the key
+type DuplicateKeyError struct {
+	Path string
+	Pos  int
+}
+
+func (i DuplicateKeyError) Error() string {
+	return "JsonStruct: duplicate key " + strconv.Quote(i.Path) + " at position " + strconv.FormatInt(int64(i.Pos), 10)
+}
+
+// TypeMismatchError reports value of Actual type requested as Expected one by StrictOps, Expected True stands for Bool
+type TypeMismatchError struct {
+	Expected Type
+	Actual   Type
+}
+
+func (i TypeMismatchError) Error() string {
+	return "JsonStruct: type mismatch, expected " + i.Expected.String() + ", actual " + i.Actual.String()
+}
+
 var OffsetOutOfRangeError = errors.New("JStructReader: offset out of range")
@@ -3,6 +3,7 @@ package experiment
 import (
 	djs "github.com/Pencroff/JsonStruct"
 	h "github.com/Pencroff/JsonStruct/helper"
+	"iter"
 	"strconv"
 	"time"
 	"unsafe"
@@ -11,6 +12,7 @@ import (
 //region JsonStructPtr
 type JsonStructPtr struct {
 	valType djs.Type
+	parsed  bool // ptr of Time value points to timeValue
 	// data
 	ptr unsafe.Pointer
 }
@@ -80,10 +82,10 @@ func (s *JsonStructPtr) Bool() bool {
 	case djs.True:
 		return true
 	case djs.Int:
-		v := *(*int)(s.ptr)
+		v := *(*int64)(s.ptr)
 		return v != 0
 	case djs.Uint:
-		v := *(*uint)(s.ptr)
+		v := *(*uint64)(s.ptr)
 		return v != 0
 	case djs.Float:
 		v := *(*float64)(s.ptr)
@@ -119,10 +121,11 @@ func (s *JsonStructPtr) Int() int64 {
 	case djs.Int:
 		return *(*int64)(s.ptr)
 	case djs.Uint:
-		return *(*int64)(s.ptr)
+		n, _ := h.UintToInt(*(*uint64)(s.ptr))
+		return n
 	case djs.Float:
-		v := *(*float64)(s.ptr)
-		return int64(v)
+		n, _ := h.FloatTruncToInt(*(*float64)(s.ptr))
+		return n
 	case djs.String:
 		v := *(*string)(s.ptr)
 		n, _ := h.StringToInt(v)
@@ -149,19 +152,21 @@ func (s *JsonStructPtr) Uint() uint64 {
 	case djs.True:
 		return 1
 	case djs.Int:
-		return *(*uint64)(s.ptr)
+		n, _ := h.IntToUint(*(*int64)(s.ptr))
+		return n
 	case djs.Uint:
 		return *(*uint64)(s.ptr)
 	case djs.Float:
-		v := *(*float64)(s.ptr)
-		return uint64(v)
+		n, _ := h.FloatTruncToUint(*(*float64)(s.ptr))
+		return n
 	case djs.String:
 		v := *(*string)(s.ptr)
 		n, _ := h.StringToUint(v)
 		return n
 	case djs.Time:
 		v := *(*time.Time)(s.ptr)
-		return uint64(v.UnixMilli())
+		n, _ := h.IntToUint(v.UnixMilli())
+		return n
 	}
 }
 
@@ -181,10 +186,10 @@ func (s *JsonStructPtr) Float() float64 {
 	case djs.True:
 		return 1
 	case djs.Int:
-		v := *(*int)(s.ptr)
+		v := *(*int64)(s.ptr)
 		return float64(v)
 	case djs.Uint:
-		v := *(*uint)(s.ptr)
+		v := *(*uint64)(s.ptr)
 		return float64(v)
 	case djs.Float:
 		return *(*float64)(s.ptr)
@@ -241,9 +246,30 @@ func (s *JsonStructPtr) IsTime() bool {
 
 func (s *JsonStructPtr) SetTime(v time.Time) {
 	s.valType = djs.Time
+	s.parsed = false
 	s.ptr = unsafe.Pointer(&v)
 }
 
+// timeValue keeps time with JSON token it parsed from,
+// t is the first field so ptr of Time value can be read as *time.Time
+type timeValue struct {
+	t   time.Time
+	raw string
+}
+
+func (s *JsonStructPtr) SetParsedTime(v time.Time, raw string) {
+	s.valType = djs.Time
+	s.parsed = true
+	s.ptr = unsafe.Pointer(&timeValue{t: v, raw: raw})
+}
+
+func (s *JsonStructPtr) TimeSource() string {
+	if s.valType != djs.Time || !s.parsed {
+		return ""
+	}
+	return (*timeValue)(s.ptr).raw
+}
+
 func (s *JsonStructPtr) Time() time.Time {
 	switch s.valType {
 	default:
@@ -432,6 +458,40 @@ func (s *JsonStructPtr) AsArray() {
 
 //endregion Array operations
 
+//region Iteration operations
+
+func (s *JsonStructPtr) ForEachKey(fn func(key string, v djs.JStructOps) bool) {
+	if s.valType != djs.Object {
+		return
+	}
+	for k, v := range *(*map[string]djs.JStructOps)(s.ptr) {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+func (s *JsonStructPtr) ForEachIndex(fn func(idx int, v djs.JStructOps) bool) {
+	if s.valType != djs.Array {
+		return
+	}
+	for k, v := range *(*[]djs.JStructOps)(s.ptr) {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+func (s *JsonStructPtr) Entries() iter.Seq2[string, djs.JStructOps] {
+	return s.ForEachKey
+}
+
+func (s *JsonStructPtr) All() iter.Seq2[int, djs.JStructOps] {
+	return s.ForEachIndex
+}
+
+//endregion Iteration operations
+
 // region Helper functions
 
 func (s *JsonStructPtr) populatePjs(v interface{}, pjs djs.JStructOps) (djs.JStructOps, error) {
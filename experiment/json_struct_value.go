@@ -3,6 +3,7 @@ package experiment
 import (
 	djs "github.com/Pencroff/JsonStruct"
 	h "github.com/Pencroff/JsonStruct/helper"
+	"iter"
 	"strconv"
 	"time"
 )
@@ -14,7 +15,7 @@ type JsonStructValue struct {
 	iNum int64
 	uNum uint64
 	fNum float64
-	str  string
+	str  string // also JSON token of parsed time
 	tm   time.Time
 	// ref
 	props map[string]djs.JStructOps
@@ -147,9 +148,11 @@ func (s *JsonStructValue) Int() int64 {
 	case djs.Int:
 		return s.iNum
 	case djs.Uint:
-		return int64(s.uNum)
+		n, _ := h.UintToInt(s.uNum)
+		return n
 	case djs.Float:
-		return int64(s.fNum)
+		n, _ := h.FloatTruncToInt(s.fNum)
+		return n
 	case djs.String:
 		n, _ := h.StringToInt(s.str)
 		return n
@@ -174,16 +177,19 @@ func (s *JsonStructValue) Uint() uint64 {
 	case djs.True:
 		return 1
 	case djs.Int:
-		return uint64(s.iNum)
+		n, _ := h.IntToUint(s.iNum)
+		return n
 	case djs.Uint:
 		return s.uNum
 	case djs.Float:
-		return uint64(s.fNum)
+		n, _ := h.FloatTruncToUint(s.fNum)
+		return n
 	case djs.String:
 		n, _ := h.StringToUint(s.str)
 		return n
 	case djs.Time:
-		return uint64(s.tm.UnixMilli())
+		n, _ := h.IntToUint(s.tm.UnixMilli())
+		return n
 	}
 }
 
@@ -257,6 +263,19 @@ func (s *JsonStructValue) IsTime() bool {
 func (s *JsonStructValue) SetTime(v time.Time) {
 	s.valType = djs.Time
 	s.tm = v
+	s.str = ""
+}
+
+func (s *JsonStructValue) SetParsedTime(v time.Time, raw string) {
+	s.SetTime(v)
+	s.str = raw
+}
+
+func (s *JsonStructValue) TimeSource() string {
+	if s.valType != djs.Time {
+		return ""
+	}
+	return s.str
 }
 
 func (s *JsonStructValue) Time() time.Time {
@@ -396,6 +415,40 @@ func (s *JsonStructValue) GetIndex(i int) djs.JStructOps {
 	return m[i]
 }
 
+//region Iteration operations
+
+func (s *JsonStructValue) ForEachKey(fn func(key string, v djs.JStructOps) bool) {
+	if s.valType != djs.Object {
+		return
+	}
+	for k, v := range s.props {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+func (s *JsonStructValue) ForEachIndex(fn func(idx int, v djs.JStructOps) bool) {
+	if s.valType != djs.Array {
+		return
+	}
+	for k, v := range s.elms {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+func (s *JsonStructValue) Entries() iter.Seq2[string, djs.JStructOps] {
+	return s.ForEachKey
+}
+
+func (s *JsonStructValue) All() iter.Seq2[int, djs.JStructOps] {
+	return s.ForEachIndex
+}
+
+//endregion Iteration operations
+
 func (s *JsonStructValue) populateVjs(v interface{}, vjs djs.JStructOps) (djs.JStructOps, error) {
 	switch data := v.(type) {
 	case djs.JStructOps:
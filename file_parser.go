@@ -0,0 +1,41 @@
+package JsonStruct
+
+import "os"
+
+// ParseFile parses JSON file at path into v.
+// Regular files are memory mapped where supported and parsed by zero-copy scanner,
+// otherwise data is read by buffered scanner.
+// Parsed strings are copied, so the mapping is released before return.
+func ParseFile(path string, v JStructOps) error {
+	return ParseFileWithOptions(path, v, ParseOptions{})
+}
+
+// ParseFileWithOptions works as ParseFile and applies limits, syntax and time options of opts.
+// AliasInput ignored as the mapping is released before return.
+//
+// Mapping is shared with the file, if other process truncates the file while it is parsed,
+// access to the lost pages raises SIGBUS and crashes the program. Parse files which can be
+// changed concurrently by JStructParseWithOptions with opened file instead.
+func ParseFileWithOptions(path string, v JStructOps, opts ParseOptions) error {
+	f, e := os.Open(path)
+	if e != nil {
+		return e
+	}
+	defer f.Close()
+	fi, e := f.Stat()
+	if e != nil {
+		return e
+	}
+	if fi.Mode().IsRegular() && fi.Size() > 0 {
+		b, e := mmapFile(f, fi.Size())
+		if e == nil {
+			opts.AliasInput = false
+			e = JStructParseBytesWithOptions(b, v, opts)
+			if ue := munmapFile(b); e == nil {
+				e = ue
+			}
+			return e
+		}
+	}
+	return JStructParseWithOptions(f, v, opts)
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+// +build !linux
+
+package JsonStruct
+
+import (
+	"errors"
+	"os"
+)
+
+var errMmapUnsupported = errors.New("JsonStruct: mmap unsupported")
+
+// mmapFile is not supported, ParseFile falls back to buffered reading
+func mmapFile(f *os.File, size int64) ([]byte, error) {
+	return nil, errMmapUnsupported
+}
+
+func munmapFile(b []byte) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+//go:build linux
+// +build linux
+
+package JsonStruct
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps file into memory as read only, pages are shared with the file and
+// reading beyond its end after truncation raises SIGBUS
+func mmapFile(f *os.File, size int64) ([]byte, error) {
+	if int64(int(size)) != size {
+		return nil, syscall.EFBIG
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func munmapFile(b []byte) error {
+	return syscall.Munmap(b)
+}
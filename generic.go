@@ -0,0 +1,233 @@
+package JsonStruct
+
+import (
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// Get resolves path of object keys (string) and array indexes (int) from js and converts found value to T,
+// ok is false if path not found or value not convertible
+func Get[T any](js JStructOps, path ...interface{}) (T, bool) {
+	return As[T](lookup(js, path))
+}
+
+// MustGet is Get panicking with PathNotFoundError or ConversionError
+func MustGet[T any](js JStructOps, path ...interface{}) T {
+	v := lookup(js, path)
+	if v == nil {
+		panic(PathNotFoundError)
+	}
+	res, ok := As[T](v)
+	if !ok {
+		panic(ConversionError)
+	}
+	return res
+}
+
+// GetOr is Get returning def if path not found or value not convertible
+func GetOr[T any](js JStructOps, def T, path ...interface{}) T {
+	res, ok := Get[T](js, path...)
+	if !ok {
+		return def
+	}
+	return res
+}
+
+// As converts js to T with strict conversions of StrictOps, integers checked for overflow of T.
+// Supported T are bool, integer and float types, string, time.Time, []byte for Binary,
+// slices and maps with string keys of supported types, and interfaces implemented by js (e.g. JStructOps).
+func As[T any](js JStructOps) (T, bool) {
+	var res T
+	if js == nil {
+		return res, false
+	}
+	if ok, done := asScalar(js, &res); done {
+		return res, ok
+	}
+	rv, ok := convertTo(js, reflect.TypeOf((*T)(nil)).Elem())
+	if !ok {
+		return res, false
+	}
+	return rv.Interface().(T), true
+}
+
+// asScalar converts js to value of p without reflection for scalar types and time.Time,
+// done is false for other types, p left unchanged if conversion fails
+func asScalar(js JStructOps, p interface{}) (ok, done bool) {
+	switch p := p.(type) {
+	case *bool:
+		return asE(js, p, boolE), true
+	case *int:
+		return asInt(js, p), true
+	case *int8:
+		return asInt(js, p), true
+	case *int16:
+		return asInt(js, p), true
+	case *int32:
+		return asInt(js, p), true
+	case *int64:
+		return asE(js, p, intE), true
+	case *uint:
+		return asUint(js, p), true
+	case *uint8:
+		return asUint(js, p), true
+	case *uint16:
+		return asUint(js, p), true
+	case *uint32:
+		return asUint(js, p), true
+	case *uint64:
+		return asE(js, p, uintE), true
+	case *float32:
+		f, e := floatE(js)
+		if e != nil || math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+			return false, true
+		}
+		*p = float32(f)
+		return true, true
+	case *float64:
+		return asE(js, p, floatE), true
+	case *string:
+		return asE(js, p, stringE), true
+	case *time.Time:
+		return asE(js, p, timeE), true
+	}
+	return false, false
+}
+
+// asE sets result of strict accessor fn to p unless it fails
+func asE[V any](js JStructOps, p *V, fn func(JStructOps) (V, error)) bool {
+	v, e := fn(js)
+	if e != nil {
+		return false
+	}
+	*p = v
+	return true
+}
+
+func asInt[N int | int8 | int16 | int32](js JStructOps, p *N) bool {
+	n, e := intE(js)
+	if e != nil || int64(N(n)) != n {
+		return false
+	}
+	*p = N(n)
+	return true
+}
+
+func asUint[N uint | uint8 | uint16 | uint32](js JStructOps, p *N) bool {
+	n, e := uintE(js)
+	if e != nil || uint64(N(n)) != n {
+		return false
+	}
+	*p = N(n)
+	return true
+}
+
+// lookup returns value at path or nil if any step missing
+func lookup(js JStructOps, path []interface{}) JStructOps {
+	for _, p := range path {
+		if js == nil {
+			return nil
+		}
+		switch k := p.(type) {
+		case string:
+			js = js.GetKey(k)
+		case int:
+			if k < 0 {
+				return nil
+			}
+			js = js.GetIndex(k)
+		default:
+			return nil
+		}
+	}
+	return js
+}
+
+// convertTo converts v to value of type t
+func convertTo(v JStructOps, t reflect.Type) (reflect.Value, bool) {
+	if v == nil {
+		return reflect.Value{}, false
+	}
+	switch t {
+	case timeType:
+		tm, e := timeE(v)
+		return reflect.ValueOf(tm), e == nil
+	case bytesType:
+		if bv, ok := v.(BinaryOps); ok && bv.IsBinary() {
+			return reflect.ValueOf(bv.Bytes()), true
+		}
+	}
+	rv := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		b, e := boolE(v)
+		if e != nil {
+			return rv, false
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, e := intE(v)
+		if e != nil || rv.OverflowInt(n) {
+			return rv, false
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, e := uintE(v)
+		if e != nil || rv.OverflowUint(n) {
+			return rv, false
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, e := floatE(v)
+		if e != nil || rv.OverflowFloat(f) {
+			return rv, false
+		}
+		rv.SetFloat(f)
+	case reflect.String:
+		s, e := stringE(v)
+		if e != nil {
+			return rv, false
+		}
+		rv.SetString(s)
+	case reflect.Slice:
+		if v.Type() != Array {
+			return rv, false
+		}
+		l := v.Size()
+		rv = reflect.MakeSlice(t, l, l)
+		for i := 0; i < l; i++ {
+			el, ok := convertTo(v.GetIndex(i), t.Elem())
+			if !ok {
+				return rv, false
+			}
+			rv.Index(i).Set(el)
+		}
+	case reflect.Map:
+		if v.Type() != Object || t.Key().Kind() != reflect.String {
+			return rv, false
+		}
+		keys := v.Keys()
+		rv = reflect.MakeMapWithSize(t, len(keys))
+		for _, k := range keys {
+			el, ok := convertTo(v.GetKey(k), t.Elem())
+			if !ok {
+				return rv, false
+			}
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), el)
+		}
+	case reflect.Interface:
+		if !reflect.TypeOf(v).Implements(t) {
+			return rv, false
+		}
+		rv.Set(reflect.ValueOf(v))
+	default:
+		return rv, false
+	}
+	return rv, true
+}
@@ -1,6 +1,6 @@
 module github.com/Pencroff/JsonStruct
 
-go 1.17
+go 1.23
 
 require github.com/stretchr/testify v1.8.0
 
@@ -50,9 +50,52 @@ var NumCh = [256]bool{
 	'9': true,
 }
 
+// NumberCh contains all characters of JSON number and hex number
+var NumberCh = [256]bool{
+	'0': true,
+	'1': true,
+	'2': true,
+	'3': true,
+	'4': true,
+	'5': true,
+	'6': true,
+	'7': true,
+	'8': true,
+	'9': true,
+	'.': true,
+	'e': true,
+	'E': true,
+	'+': true,
+	'-': true,
+	// hex numbers of relaxed mode
+	'x': true,
+	'X': true,
+	'a': true,
+	'b': true,
+	'c': true,
+	'd': true,
+	'f': true,
+	'A': true,
+	'B': true,
+	'C': true,
+	'D': true,
+	'F': true,
+}
+
 var NullTokenData = []byte(`null`)
 var FalseTokenData = []byte(`false`)
 var TrueTokenData = []byte(`true`)
+var InfinityTokenData = []byte(`Infinity`)
+var NaNTokenData = []byte(`NaN`)
+
+// IdentCh contains characters of unquoted key (JSON5), bytes of non ASCII characters included
+var IdentCh = func() (res [256]bool) {
+	for i := 0; i < 256; i++ {
+		res[i] = i >= 0x80 || i == '_' || i == '$' ||
+			i >= 'a' && i <= 'z' || i >= 'A' && i <= 'Z' || i >= '0' && i <= '9'
+	}
+	return
+}()
 
 const (
 	BackSlashCh      = 0x5c // '\'
@@ -65,10 +108,15 @@ const (
 	OpenBracketCh    = 0x5b // '['
 	CloseBracketCh   = 0x5d // ']'
 	CommaCh          = 0x2c // ','
+	ColonCh          = 0x3a // ':'
 	PointCh          = 0x2e // '.'
 	ExpCh            = 0x45 // 'E'
 	ExpSmCh          = 0x65 // 'e'
 	MinusCh          = 0x2d // '-'
+	PlusCh           = 0x2b // '+'
+	SingleQuoteCh    = 0x27 // '\''
+	SlashCh          = 0x2f // '/'
+	AsteriskCh       = 0x2a // '*'
 )
 
 const (
@@ -3,6 +3,7 @@ package helper
 import (
 	"fmt"
 	"github.com/stretchr/testify/suite"
+	"math"
 	"strconv"
 	"testing"
 )
@@ -25,6 +26,68 @@ func (s *ConverterTestSuite) TestFloatToInt() {
 	s.Equal(int64(-2), FloatToInt(-1.99))
 }
 
+func (s *ConverterTestSuite) TestFloatTruncToInt() {
+	tbl := []struct {
+		val float64
+		res int64
+		ok  bool
+	}{
+		{0, 0, true},
+		{1.99, 1, true},
+		{-1.99, -1, true},
+		{float64(MaxSafeInt), MaxSafeInt, true},
+		{-9223372036854775808.0, MinInt, true},
+		{9223372036854775808.0, MaxInt, false},
+		{-9223372036854777856.0, MinInt, false},
+		{math.Inf(1), MaxInt, false},
+		{math.Inf(-1), MinInt, false},
+		{math.NaN(), 0, false},
+	}
+	for _, el := range tbl {
+		res, ok := FloatTruncToInt(el.val)
+		s.Equal(el.res, res, "FloatTruncToInt(%v)", el.val)
+		s.Equal(el.ok, ok, "FloatTruncToInt(%v)", el.val)
+	}
+}
+
+func (s *ConverterTestSuite) TestFloatTruncToUint() {
+	tbl := []struct {
+		val float64
+		res uint64
+		ok  bool
+	}{
+		{0, 0, true},
+		{1.99, 1, true},
+		{-0.99, 0, true},
+		{-1, 0, false},
+		{18446744073709549568.0, 18446744073709549568, true},
+		{18446744073709551616.0, MaxUint, false},
+		{math.Inf(1), MaxUint, false},
+		{math.Inf(-1), 0, false},
+		{math.NaN(), 0, false},
+	}
+	for _, el := range tbl {
+		res, ok := FloatTruncToUint(el.val)
+		s.Equal(el.res, res, "FloatTruncToUint(%v)", el.val)
+		s.Equal(el.ok, ok, "FloatTruncToUint(%v)", el.val)
+	}
+}
+
+func (s *ConverterTestSuite) TestIntUintConversion() {
+	u, ok := IntToUint(MaxInt)
+	s.Equal(MaxIntUint, u)
+	s.True(ok)
+	u, ok = IntToUint(-1)
+	s.Equal(uint64(0), u)
+	s.False(ok)
+	i, ok := UintToInt(MaxIntUint)
+	s.Equal(MaxInt, i)
+	s.True(ok)
+	i, ok = UintToInt(MinIntUint)
+	s.Equal(MaxInt, i)
+	s.False(ok)
+}
+
 func (s *ConverterTestSuite) TestStringToInt() {
 	str := "-123456789"
 	i, err := strconv.Atoi(str)
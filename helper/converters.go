@@ -9,6 +9,50 @@ func FloatToInt(val float64) int64 {
 	return int64(val + 0.5)
 }
 
+// FloatTruncToInt - truncates float toward zero saturating at MinInt and MaxInt,
+// ok is false for NaN and values out of range
+func FloatTruncToInt(val float64) (int64, bool) {
+	switch {
+	case val != val:
+		return 0, false
+	case val >= -float64(MinInt):
+		return MaxInt, false
+	case val < float64(MinInt):
+		return MinInt, false
+	}
+	return int64(val), true
+}
+
+// FloatTruncToUint - truncates float toward zero saturating at 0 and MaxUint,
+// ok is false for NaN and values out of range
+func FloatTruncToUint(val float64) (uint64, bool) {
+	switch {
+	case val != val:
+		return 0, false
+	case val >= float64(MaxUint):
+		return MaxUint, false
+	case val <= -1:
+		return 0, false
+	}
+	return uint64(val), true
+}
+
+// IntToUint - converts int saturating negative values at 0, ok is false if value clamped
+func IntToUint(val int64) (uint64, bool) {
+	if val < 0 {
+		return 0, false
+	}
+	return uint64(val), true
+}
+
+// UintToInt - converts uint saturating at MaxInt, ok is false if value clamped
+func UintToInt(val uint64) (int64, bool) {
+	if val > MaxIntUint {
+		return MaxInt, false
+	}
+	return int64(val), true
+}
+
 // StringToInt - gjson inspiration with optimizations for behavior similar to JavaScript JSON.parse
 // https://github.com/tidwall/gjson/blob/e20a0bfa61962f4b430a2187ee5fbffe17f06856/gjson.go#L2583
 func StringToInt(s string) (int64, bool) {
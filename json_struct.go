@@ -1,7 +1,9 @@
 package JsonStruct
 
 import (
+	"encoding/base64"
 	h "github.com/Pencroff/JsonStruct/helper"
+	"iter"
 	"strconv"
 	"time"
 	"unsafe"
@@ -62,6 +64,8 @@ func (s *JsonStruct) Value() interface{} {
 		return *(*map[string]JStructOps)(s.ptr)
 	case Array:
 		return *(*[]JStructOps)(s.ptr)
+	case Binary:
+		return *(*[]byte)(s.ptr)
 	}
 }
 
@@ -78,6 +82,9 @@ func (s *JsonStruct) Size() int {
 	case Array:
 		v := *(*[]JStructOps)(s.ptr)
 		return len(v)
+	case Binary:
+		v := *(*[]byte)(s.ptr)
+		return len(v)
 	}
 }
 
@@ -101,10 +108,10 @@ func (s *JsonStruct) Bool() bool {
 	case True:
 		return true
 	case Int:
-		v := *(*int)(s.ptr)
+		v := *(*int64)(s.ptr)
 		return v != 0
 	case Uint:
-		v := *(*uint)(s.ptr)
+		v := *(*uint64)(s.ptr)
 		return v != 0
 	case Float:
 		v := *(*float64)(s.ptr)
@@ -115,6 +122,9 @@ func (s *JsonStruct) Bool() bool {
 	case Time:
 		v := *(*time.Time)(s.ptr)
 		return v.UnixMilli() != 0
+	case Binary:
+		v := *(*[]byte)(s.ptr)
+		return len(v) != 0
 	}
 }
 
@@ -131,6 +141,7 @@ func (s *JsonStruct) SetInt(v int64) {
 	s.ptr = unsafe.Pointer(&v)
 }
 
+// Int returns value as int64, numbers out of range saturate and floats truncated toward zero
 func (s *JsonStruct) Int() int64 {
 	switch s.valType {
 	default:
@@ -140,10 +151,11 @@ func (s *JsonStruct) Int() int64 {
 	case Int:
 		return *(*int64)(s.ptr)
 	case Uint:
-		return *(*int64)(s.ptr)
+		n, _ := h.UintToInt(*(*uint64)(s.ptr))
+		return n
 	case Float:
-		v := *(*float64)(s.ptr)
-		return int64(v)
+		n, _ := h.FloatTruncToInt(*(*float64)(s.ptr))
+		return n
 	case String:
 		v := *(*string)(s.ptr)
 		n, _ := h.StringToInt(v)
@@ -163,6 +175,7 @@ func (s *JsonStruct) SetUint(v uint64) {
 	s.ptr = unsafe.Pointer(&v)
 }
 
+// Uint returns value as uint64, negative numbers saturate at 0 and floats truncated toward zero
 func (s *JsonStruct) Uint() uint64 {
 	switch s.valType {
 	default:
@@ -170,19 +183,21 @@ func (s *JsonStruct) Uint() uint64 {
 	case True:
 		return 1
 	case Int:
-		return *(*uint64)(s.ptr)
+		n, _ := h.IntToUint(*(*int64)(s.ptr))
+		return n
 	case Uint:
 		return *(*uint64)(s.ptr)
 	case Float:
-		v := *(*float64)(s.ptr)
-		return uint64(v)
+		n, _ := h.FloatTruncToUint(*(*float64)(s.ptr))
+		return n
 	case String:
 		v := *(*string)(s.ptr)
 		n, _ := h.StringToUint(v)
 		return n
 	case Time:
 		v := *(*time.Time)(s.ptr)
-		return uint64(v.UnixMilli())
+		n, _ := h.IntToUint(v.UnixMilli())
+		return n
 	}
 }
 
@@ -202,10 +217,10 @@ func (s *JsonStruct) Float() float64 {
 	case True:
 		return 1
 	case Int:
-		v := *(*int)(s.ptr)
+		v := *(*int64)(s.ptr)
 		return float64(v)
 	case Uint:
-		v := *(*uint)(s.ptr)
+		v := *(*uint64)(s.ptr)
 		return float64(v)
 	case Float:
 		return *(*float64)(s.ptr)
@@ -247,8 +262,18 @@ func (s *JsonStruct) String() string {
 	case String:
 		return *(*string)(s.ptr)
 	case Time:
-		v := *(*time.Time)(s.ptr)
-		return v.Format(time.RFC3339)
+		v := (*timeValue)(s.ptr)
+		if v.raw != "" {
+			// original text of parsed value
+			if v.raw[0] == h.QuoteCh {
+				return unquote([]byte(v.raw))
+			}
+			return v.raw
+		}
+		return v.t.Format(time.RFC3339)
+	case Binary:
+		v := *(*[]byte)(s.ptr)
+		return base64.StdEncoding.EncodeToString(v)
 	case Object:
 		return "[object]"
 	case Array:
@@ -262,7 +287,28 @@ func (s *JsonStruct) IsTime() bool {
 
 func (s *JsonStruct) SetTime(v time.Time) {
 	s.valType = Time
-	s.ptr = unsafe.Pointer(&v)
+	s.ptr = unsafe.Pointer(&timeValue{t: v})
+}
+
+// timeValue keeps time with JSON token it parsed from,
+// t is the first field so ptr of Time value can be read as *time.Time
+type timeValue struct {
+	t   time.Time
+	raw string // empty for values set by SetTime
+}
+
+// SetParsedTime sets time v parsed from JSON token raw
+func (s *JsonStruct) SetParsedTime(v time.Time, raw string) {
+	s.valType = Time
+	s.ptr = unsafe.Pointer(&timeValue{t: v, raw: raw})
+}
+
+// TimeSource returns JSON token of parsed Time value
+func (s *JsonStruct) TimeSource() string {
+	if s.valType != Time {
+		return ""
+	}
+	return (*timeValue)(s.ptr).raw
 }
 
 func (s *JsonStruct) Time() time.Time {
@@ -278,6 +324,33 @@ func (s *JsonStruct) Time() time.Time {
 	}
 }
 
+func (s *JsonStruct) IsBinary() bool {
+	return s.valType == Binary
+}
+
+// SetBytes sets binary value, v is kept without copy
+func (s *JsonStruct) SetBytes(v []byte) {
+	s.valType = Binary
+	s.ptr = unsafe.Pointer(&v)
+}
+
+// Bytes returns binary value, strings decoded from standard base64, nil for invalid ones
+func (s *JsonStruct) Bytes() []byte {
+	switch s.valType {
+	default:
+		return nil
+	case String:
+		v := *(*string)(s.ptr)
+		b, e := base64.StdEncoding.DecodeString(v)
+		if e != nil {
+			return nil
+		}
+		return b
+	case Binary:
+		return *(*[]byte)(s.ptr)
+	}
+}
+
 func (s *JsonStruct) IsNull() bool {
 	return s.valType == Null
 }
@@ -289,6 +362,34 @@ func (s *JsonStruct) SetNull() {
 
 //endregion Primitive operations
 
+//region Strict operations
+
+func (s *JsonStruct) BoolE() (bool, error) {
+	return boolE(s)
+}
+
+func (s *JsonStruct) IntE() (int64, error) {
+	return intE(s)
+}
+
+func (s *JsonStruct) UintE() (uint64, error) {
+	return uintE(s)
+}
+
+func (s *JsonStruct) FloatE() (float64, error) {
+	return floatE(s)
+}
+
+func (s *JsonStruct) StringE() (string, error) {
+	return stringE(s)
+}
+
+func (s *JsonStruct) TimeE() (time.Time, error) {
+	return timeE(s)
+}
+
+//endregion Strict operations
+
 //region Object operations
 
 func (s *JsonStruct) SetKey(key string, v interface{}) error {
@@ -453,6 +554,40 @@ func (s *JsonStruct) AsArray() {
 
 //endregion Array operations
 
+//region Iteration operations
+
+func (s *JsonStruct) ForEachKey(fn func(key string, v JStructOps) bool) {
+	if s.valType != Object {
+		return
+	}
+	for k, v := range *(*map[string]JStructOps)(s.ptr) {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+func (s *JsonStruct) ForEachIndex(fn func(idx int, v JStructOps) bool) {
+	if s.valType != Array {
+		return
+	}
+	for k, v := range *(*[]JStructOps)(s.ptr) {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+func (s *JsonStruct) Entries() iter.Seq2[string, JStructOps] {
+	return s.ForEachKey
+}
+
+func (s *JsonStruct) All() iter.Seq2[int, JStructOps] {
+	return s.ForEachIndex
+}
+
+//endregion Iteration operations
+
 // region Helper functions
 
 func (s *JsonStruct) populatePjs(v interface{}, pjs JStructOps) (JStructOps, error) {
@@ -504,6 +639,13 @@ func (s *JsonStruct) populatePjs(v interface{}, pjs JStructOps) (JStructOps, err
 	case time.Time:
 		pjs = resolvePointer(pjs)
 		pjs.SetTime(data)
+	case []byte:
+		pjs = resolvePointer(pjs)
+		bv, ok := pjs.(BinaryOps)
+		if !ok {
+			return nil, UnsupportedTypeError
+		}
+		bv.SetBytes(data)
 	default:
 		return nil, UnsupportedTypeError
 	}
@@ -0,0 +1,552 @@
+package JsonStruct
+
+import (
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+	"iter"
+	"strconv"
+	"time"
+)
+
+// JsonStructLazy is JStructOps parsing content of objects and arrays on the first access to it.
+// Each access parses one level, nested containers stay as source text till they accessed.
+// Untouched values keep source text and serialized back as is with default SerializeOptions if parsed in strict mode.
+type JsonStructLazy struct {
+	v    JsonStruct   // value, containers store loaded children
+	raw  []byte       // source text, nil if value changed
+	lazy bool         // container content not loaded yet
+	opts *lazyOptions // options of source parsing, nil for values set by caller
+	path string       // JSON Pointer of container, maintained for BinaryPaths only
+}
+
+// lazyOptions keeps ParseOptions of lazy tree applied to values of every loaded level
+type lazyOptions struct {
+	ParseOptions
+	binary map[string]bool // ParseOptions.BinaryPaths
+}
+
+// JStructParseLazy validates b and returns root node of lazy tree, values point to b with opts.AliasInput,
+// otherwise to copy of it. Repeated keys resolved as DuplicateKeysLastWins, whitespaces around root value dropped.
+// Time and binary options applied to values of each level on its loading, Spans are not recorded.
+func JStructParseLazy(b []byte, opts ParseOptions) (*JsonStructLazy, error) {
+	src := b
+	if !opts.AliasInput {
+		src = append([]byte{}, b...)
+	}
+	o := &lazyOptions{ParseOptions: opts}
+	if len(opts.BinaryPaths) > 0 {
+		o.binary = make(map[string]bool, len(opts.BinaryPaths))
+		for _, p := range opts.BinaryPaths {
+			o.binary[p] = true
+		}
+	}
+	tc := NewJStructTokenizerWithOptions(NewJStructBytesScanner(src), opts).(*JStructTokenizerImpl)
+	var root *JsonStructLazy
+	e := readLazyLevel(tc, src, o, nil, func(_ string, n *JsonStructLazy) {
+		root = n
+	})
+	if e != nil {
+		return nil, e
+	}
+	if root == nil {
+		return nil, InvalidJsonError{Err: io.EOF}
+	}
+	return root, nil
+}
+
+// readLazyLevel reads values of parent container (nil for root value) till the end of container or data,
+// nested containers passed to fn with their source text
+func readLazyLevel(tc *JStructTokenizerImpl, src []byte, o *lazyOptions, parent *JsonStructLazy, fn func(key string, n *JsonStructLazy)) error {
+	depth := 0
+	start := 0
+	idx := 0
+	var key string
+	emit := func(n *JsonStructLazy) {
+		fn(key, n)
+		idx++
+	}
+	for {
+		e := tc.Next()
+		if e == io.EOF {
+			return nil
+		}
+		if e != nil {
+			return e
+		}
+		level := tc.Level()
+		switch level {
+		case LevelKey:
+			if depth == 0 {
+				key = unquoteKey(tc.Kind(), tc.Value())
+			}
+			continue
+		case LevelArray, LevelObject:
+			if depth == 0 {
+				start = tc.Start()
+			}
+			depth++
+			continue
+		case LevelArrayEnd, LevelObjectEnd:
+			if depth == 0 {
+				return nil
+			}
+			depth--
+			if depth == 0 {
+				emit(o.container(src[start:tc.Start()+1], o.pointer(parent, key, idx)))
+			}
+			continue
+		}
+		if depth == 0 {
+			n := &JsonStructLazy{}
+			e = o.value(tc.Kind(), tc.Value(), n, parent, key, idx)
+			if e != nil {
+				return e
+			}
+			n.raw = tc.Value()
+			n.opts = o
+			emit(n)
+		}
+		if level == LevelValueLast {
+			if depth == 0 {
+				return nil
+			}
+			depth--
+			if depth == 0 {
+				// closing bracket consumed as delimiter of the last value
+				emit(o.container(src[start:tc.sc.Index()+1], o.pointer(parent, key, idx)))
+			}
+		}
+	}
+}
+
+func (o *lazyOptions) container(raw []byte, path string) *JsonStructLazy {
+	n := &JsonStructLazy{raw: raw, lazy: true, opts: o, path: path}
+	n.v.valType = Array
+	if raw[0] == h.OpenBraceCh {
+		n.v.valType = Object
+	}
+	return n
+}
+
+// value sets primitive value of token to n, item key or idx of parent, same as jStructBuilder.value
+func (o *lazyOptions) value(kind TokenizerKind, b []byte, n *JsonStructLazy, parent *JsonStructLazy, key string, idx int) error {
+	switch kind {
+	case KindString:
+		if o.binary != nil && o.binary[o.pointer(parent, key, idx)] && parseBinary(b, n) {
+			return nil
+		}
+		if len(o.TimeLayouts) > 0 && parseTimeLayouts(b, o.TimeLayouts, n) {
+			return nil
+		}
+	case KindNumber, KindFloatNumber:
+		if o.TimeEpochKeys == nil || parent == nil || parent.v.valType != Object {
+			break
+		}
+		if unit, ok := o.TimeEpochKeys[key]; ok && parseEpoch(kind, b, unit, n) {
+			return nil
+		}
+	}
+	return parseTokenValue(kind, b, n, true)
+}
+
+// pointer returns JSON Pointer of item key or idx of parent, empty without BinaryPaths
+func (o *lazyOptions) pointer(parent *JsonStructLazy, key string, idx int) string {
+	if o.binary == nil || parent == nil {
+		return ""
+	}
+	if parent.v.valType == Array {
+		key = strconv.Itoa(idx)
+	}
+	return parent.path + "/" + pointerEscaper.Replace(key)
+}
+
+// unquoteKey resolves key token, unquoted keys of relaxed mode are literals
+func unquoteKey(kind TokenizerKind, b []byte) string {
+	if kind == KindLiteral {
+		return string(b)
+	}
+	return unquote(b)
+}
+
+// load parses content of lazy container
+func (s *JsonStructLazy) load() {
+	if !s.lazy {
+		return
+	}
+	s.lazy = false
+	tp := s.v.valType
+	s.v.SetNull()
+	if tp == Object {
+		s.v.AsObject()
+	} else {
+		s.v.AsArray()
+	}
+	// limits checked by validation of the whole source
+	opts := ParseOptions{Relaxed: s.opts.Relaxed, NoTimeDetection: s.opts.NoTimeDetection}
+	tc := NewJStructTokenizerWithOptions(NewJStructBytesScanner(s.raw), opts).(*JStructTokenizerImpl)
+	// opening bracket
+	tc.Next()
+	// data validated by JStructParseLazy
+	readLazyLevel(tc, s.raw, s.opts, s, func(key string, n *JsonStructLazy) {
+		if tp == Object {
+			s.v.SetKey(key, n)
+		} else {
+			s.v.Push(n)
+		}
+	})
+}
+
+// change loads content and drops source text of container before modification
+func (s *JsonStructLazy) change() {
+	s.load()
+	s.raw = nil
+}
+
+// reset drops source text and content before new value set
+func (s *JsonStructLazy) reset() {
+	s.raw = nil
+	s.lazy = false
+}
+
+// source returns source text of value if neither it nor loaded descendants changed, nil otherwise.
+// Text parsed in relaxed mode is not returned as it may be not valid JSON.
+func (s *JsonStructLazy) source() []byte {
+	if s.opts != nil && s.opts.Relaxed {
+		return nil
+	}
+	if s.raw == nil || s.lazy {
+		return s.raw
+	}
+	switch s.v.valType {
+	case Object:
+		for _, el := range *(*map[string]JStructOps)(s.v.ptr) {
+			if el.(*JsonStructLazy).source() == nil {
+				return nil
+			}
+		}
+	case Array:
+		for _, el := range *(*[]JStructOps)(s.v.ptr) {
+			if el.(*JsonStructLazy).source() == nil {
+				return nil
+			}
+		}
+	}
+	return s.raw
+}
+
+//region Json Unmarshal / Marshal
+
+func (s *JsonStructLazy) UnmarshalJSON(b []byte) error {
+	n, e := JStructParseLazy(b, ParseOptions{})
+	if e != nil {
+		return e
+	}
+	*s = *n
+	return nil
+}
+
+// MarshalJSON returns source text of untouched values parsed in strict mode,
+// changed containers and values parsed in relaxed mode serialized in compact form
+func (s *JsonStructLazy) MarshalJSON() ([]byte, error) {
+	return appendJStruct(nil, s, SerializeOptions{})
+}
+
+//endregion
+
+//region General operations
+
+func (s *JsonStructLazy) Type() Type {
+	return s.v.valType
+}
+
+func (s *JsonStructLazy) Value() interface{} {
+	s.load()
+	return s.v.Value()
+}
+
+func (s *JsonStructLazy) Size() int {
+	s.load()
+	return s.v.Size()
+}
+
+func (s *JsonStructLazy) IsNull() bool {
+	return s.v.IsNull()
+}
+
+func (s *JsonStructLazy) SetNull() {
+	s.reset()
+	s.v.SetNull()
+}
+
+func (s *JsonStructLazy) IsObject() bool {
+	return s.v.IsObject()
+}
+
+func (s *JsonStructLazy) AsObject() {
+	if s.v.valType == Object {
+		return
+	}
+	s.reset()
+	s.v.AsObject()
+}
+
+func (s *JsonStructLazy) IsArray() bool {
+	return s.v.IsArray()
+}
+
+func (s *JsonStructLazy) AsArray() {
+	if s.v.valType == Array {
+		return
+	}
+	s.reset()
+	s.v.AsArray()
+}
+
+//endregion General operations
+
+//region Primitive operations
+
+func (s *JsonStructLazy) IsBool() bool {
+	return s.v.IsBool()
+}
+
+func (s *JsonStructLazy) SetBool(v bool) {
+	s.reset()
+	s.v.SetBool(v)
+}
+
+func (s *JsonStructLazy) Bool() bool {
+	return s.v.Bool()
+}
+
+func (s *JsonStructLazy) IsNumber() bool {
+	return s.v.IsNumber()
+}
+
+func (s *JsonStructLazy) IsInt() bool {
+	return s.v.IsInt()
+}
+
+func (s *JsonStructLazy) SetInt(v int64) {
+	s.reset()
+	s.v.SetInt(v)
+}
+
+func (s *JsonStructLazy) Int() int64 {
+	return s.v.Int()
+}
+
+func (s *JsonStructLazy) IsUint() bool {
+	return s.v.IsUint()
+}
+
+func (s *JsonStructLazy) SetUint(v uint64) {
+	s.reset()
+	s.v.SetUint(v)
+}
+
+func (s *JsonStructLazy) Uint() uint64 {
+	return s.v.Uint()
+}
+
+func (s *JsonStructLazy) IsFloat() bool {
+	return s.v.IsFloat()
+}
+
+func (s *JsonStructLazy) SetFloat(v float64) {
+	s.reset()
+	s.v.SetFloat(v)
+}
+
+func (s *JsonStructLazy) Float() float64 {
+	return s.v.Float()
+}
+
+func (s *JsonStructLazy) IsString() bool {
+	return s.v.IsString()
+}
+
+func (s *JsonStructLazy) SetString(v string) {
+	s.reset()
+	s.v.SetString(v)
+}
+
+func (s *JsonStructLazy) String() string {
+	return s.v.String()
+}
+
+func (s *JsonStructLazy) IsTime() bool {
+	return s.v.IsTime()
+}
+
+func (s *JsonStructLazy) SetTime(v time.Time) {
+	s.reset()
+	s.v.SetTime(v)
+}
+
+func (s *JsonStructLazy) Time() time.Time {
+	return s.v.Time()
+}
+
+func (s *JsonStructLazy) SetParsedTime(v time.Time, raw string) {
+	s.reset()
+	s.v.SetParsedTime(v, raw)
+}
+
+func (s *JsonStructLazy) TimeSource() string {
+	return s.v.TimeSource()
+}
+
+func (s *JsonStructLazy) IsBinary() bool {
+	return s.v.IsBinary()
+}
+
+func (s *JsonStructLazy) SetBytes(v []byte) {
+	s.reset()
+	s.v.SetBytes(v)
+}
+
+func (s *JsonStructLazy) Bytes() []byte {
+	return s.v.Bytes()
+}
+
+//endregion Primitive operations
+
+//region Strict operations
+
+func (s *JsonStructLazy) BoolE() (bool, error) {
+	return boolE(s)
+}
+
+func (s *JsonStructLazy) IntE() (int64, error) {
+	return intE(s)
+}
+
+func (s *JsonStructLazy) UintE() (uint64, error) {
+	return uintE(s)
+}
+
+func (s *JsonStructLazy) FloatE() (float64, error) {
+	return floatE(s)
+}
+
+func (s *JsonStructLazy) StringE() (string, error) {
+	return stringE(s)
+}
+
+func (s *JsonStructLazy) TimeE() (time.Time, error) {
+	return timeE(s)
+}
+
+//endregion Strict operations
+
+//region Object operations
+
+func (s *JsonStructLazy) SetKey(key string, v interface{}) error {
+	if s.v.valType != Object {
+		return NotObjectError
+	}
+	s.change()
+	return s.v.SetKey(key, lazyValue(v))
+}
+
+func (s *JsonStructLazy) GetKey(key string) JStructOps {
+	s.load()
+	return s.v.GetKey(key)
+}
+
+func (s *JsonStructLazy) RemoveKey(key string) JStructOps {
+	if s.v.valType != Object {
+		return nil
+	}
+	s.change()
+	return s.v.RemoveKey(key)
+}
+
+func (s *JsonStructLazy) HasKey(key string) bool {
+	s.load()
+	return s.v.HasKey(key)
+}
+
+func (s *JsonStructLazy) Keys() []string {
+	s.load()
+	return s.v.Keys()
+}
+
+//endregion Object operations
+
+//region Array operations
+
+func (s *JsonStructLazy) Push(v interface{}) error {
+	if s.v.valType != Array {
+		return NotArrayError
+	}
+	s.change()
+	return s.v.Push(lazyValue(v))
+}
+
+func (s *JsonStructLazy) Pop() JStructOps {
+	if s.v.valType != Array {
+		return nil
+	}
+	s.change()
+	return s.v.Pop()
+}
+
+func (s *JsonStructLazy) Shift() JStructOps {
+	if s.v.valType != Array {
+		return nil
+	}
+	s.change()
+	return s.v.Shift()
+}
+
+func (s *JsonStructLazy) SetIndex(i int, v interface{}) error {
+	if s.v.valType != Array {
+		return NotArrayError
+	}
+	s.change()
+	return s.v.SetIndex(i, lazyValue(v))
+}
+
+func (s *JsonStructLazy) GetIndex(i int) JStructOps {
+	s.load()
+	return s.v.GetIndex(i)
+}
+
+//endregion Array operations
+
+//region Iteration operations
+
+func (s *JsonStructLazy) ForEachKey(fn func(key string, v JStructOps) bool) {
+	s.load()
+	s.v.ForEachKey(fn)
+}
+
+func (s *JsonStructLazy) ForEachIndex(fn func(idx int, v JStructOps) bool) {
+	s.load()
+	s.v.ForEachIndex(fn)
+}
+
+func (s *JsonStructLazy) Entries() iter.Seq2[string, JStructOps] {
+	return s.ForEachKey
+}
+
+func (s *JsonStructLazy) All() iter.Seq2[int, JStructOps] {
+	return s.ForEachIndex
+}
+
+//endregion Iteration operations
+
+// lazyValue wraps primitive value to JsonStructLazy node, so the tree consists of lazy nodes
+func lazyValue(v interface{}) interface{} {
+	if _, ok := v.(JStructOps); ok {
+		return v
+	}
+	n := &JsonStructLazy{}
+	_, e := n.v.populatePjs(v, &n.v)
+	if e != nil {
+		return v
+	}
+	return n
+}
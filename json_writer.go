@@ -0,0 +1,209 @@
+package JsonStruct
+
+import (
+	"bufio"
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+	"strconv"
+	"time"
+)
+
+// JStructWriter writes JSON document value by value without building of tree.
+// Calls are checked against nesting state, misuse (key outside of object, value without key,
+// unbalanced End, second root value) fails with WriterStateError. The first error is sticky.
+// Output is buffered, Flush or Close should be called to complete writing.
+type JStructWriter interface {
+	BeginObject() error
+	BeginArray() error
+	End() error         // close current object or array
+	Key(k string) error // key of the next value in object
+	Int(v int64) error
+	Uint(v uint64) error
+	Float(v float64) error
+	String(v string) error
+	Time(v time.Time) error
+	Null() error
+	Bool(v bool) error
+	Bytes(v []byte) error     // binary data written as base64 string
+	Value(v JStructOps) error // whole value serialized same as JStructSerialize
+	Flush() error             // write buffered data to underlying writer
+	Close() error             // check document completed and flush
+}
+
+// NewJStructWriter creates writer of JSON document to wr
+func NewJStructWriter(wr io.Writer) JStructWriter {
+	return NewJStructWriterWithOptions(wr, SerializeOptions{})
+}
+
+// NewJStructWriterWithOptions creates writer of JSON document to wr formatted according to opts
+func NewJStructWriterWithOptions(wr io.Writer, opts SerializeOptions) JStructWriter {
+	return &JStructWriterImpl{w: bufio.NewWriter(wr), opts: opts}
+}
+
+// JStructWriterImpl keeps stack of open containers to restore delimiters between values
+type JStructWriterImpl struct {
+	w     *bufio.Writer
+	opts  SerializeOptions
+	buf   []byte               // formatted primitive
+	stack []formatterContainer // open containers
+	key   bool                 // key written, value expected
+	done  bool                 // root value completed
+	err   error
+}
+
+func (w *JStructWriterImpl) BeginObject() error {
+	return w.begin(h.OpenBraceCh, h.CloseBraceCh)
+}
+
+func (w *JStructWriterImpl) BeginArray() error {
+	return w.begin(h.OpenBracketCh, h.CloseBracketCh)
+}
+
+func (w *JStructWriterImpl) End() error {
+	l := len(w.stack) - 1
+	if w.err != nil || l < 0 || w.key {
+		return w.fail()
+	}
+	w.err = w.w.WriteByte(w.stack[l].end)
+	w.stack = w.stack[:l]
+	w.done = l == 0
+	return w.err
+}
+
+func (w *JStructWriterImpl) Key(k string) error {
+	l := len(w.stack) - 1
+	if w.err != nil || l < 0 || w.key || w.stack[l].end != h.CloseBraceCh {
+		return w.fail()
+	}
+	w.buf = w.sep(w.buf[:0])
+	w.buf = appendEscaped(w.buf, k, w.opts.Escape)
+	w.buf = append(w.buf, h.ColonCh)
+	w.key = true
+	_, w.err = w.w.Write(w.buf)
+	return w.err
+}
+
+func (w *JStructWriterImpl) Int(v int64) error {
+	return w.primitive(strconv.AppendInt(w.value(), v, 10))
+}
+
+func (w *JStructWriterImpl) Uint(v uint64) error {
+	return w.primitive(strconv.AppendUint(w.value(), v, 10))
+}
+
+func (w *JStructWriterImpl) Float(v float64) error {
+	return w.checked(appendNumber(w.value(), v, w.opts))
+}
+
+func (w *JStructWriterImpl) String(v string) error {
+	return w.primitive(appendEscaped(w.value(), v, w.opts.Escape))
+}
+
+func (w *JStructWriterImpl) Time(v time.Time) error {
+	return w.primitive(appendTime(w.value(), v, w.opts))
+}
+
+func (w *JStructWriterImpl) Null() error {
+	return w.primitive(append(w.value(), h.NullTokenData...))
+}
+
+func (w *JStructWriterImpl) Bool(v bool) error {
+	return w.primitive(strconv.AppendBool(w.value(), v))
+}
+
+func (w *JStructWriterImpl) Bytes(v []byte) error {
+	return w.primitive(appendBase64(w.value(), v, w.opts))
+}
+
+func (w *JStructWriterImpl) Value(v JStructOps) error {
+	return w.checked(appendJStruct(w.value(), v, w.opts))
+}
+
+func (w *JStructWriterImpl) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.w.Flush()
+	return w.err
+}
+
+func (w *JStructWriterImpl) Close() error {
+	if w.err == nil && !w.done {
+		return w.fail()
+	}
+	return w.Flush()
+}
+
+func (w *JStructWriterImpl) begin(open, end byte) error {
+	b := w.value()
+	if w.err != nil {
+		return w.err
+	}
+	w.buf = append(b, open)
+	w.stack = append(w.stack, formatterContainer{end: end})
+	_, w.err = w.w.Write(w.buf)
+	return w.err
+}
+
+// value starts the next value in buffer with delimiter if required,
+// state error set if value is not expected: second root value or object item without key
+func (w *JStructWriterImpl) value() []byte {
+	l := len(w.stack) - 1
+	switch {
+	case w.err != nil:
+		return nil
+	case l < 0:
+		if w.done {
+			w.fail()
+			return nil
+		}
+		return w.buf[:0]
+	case w.key:
+		w.key = false
+		return w.buf[:0]
+	case w.stack[l].end == h.CloseBraceCh:
+		w.fail()
+		return nil
+	}
+	return w.sep(w.buf[:0])
+}
+
+// primitive writes value b formatted after w.value call
+func (w *JStructWriterImpl) primitive(b []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.buf = b
+	w.done = len(w.stack) == 0
+	_, w.err = w.w.Write(b)
+	return w.err
+}
+
+// checked writes value b unless formatting failed with e
+func (w *JStructWriterImpl) checked(b []byte, e error) error {
+	if e != nil && w.err == nil {
+		w.err = e
+	}
+	return w.primitive(b)
+}
+
+// sep appends comma before every item except the first one of current container
+func (w *JStructWriterImpl) sep(dst []byte) []byte {
+	l := len(w.stack) - 1
+	if l < 0 {
+		return dst
+	}
+	c := &w.stack[l]
+	if c.n > 0 {
+		dst = append(dst, h.CommaCh)
+	}
+	c.n++
+	return dst
+}
+
+func (w *JStructWriterImpl) fail() error {
+	if w.err == nil {
+		w.err = WriterStateError
+	}
+	return w.err
+}
@@ -0,0 +1,99 @@
+package JsonStruct
+
+import (
+	"bufio"
+	"context"
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+)
+
+// JStructNDJSONReader reads newline delimited JSON (NDJSON / JSON Lines): one value per line, blank lines skipped.
+// Limits of ParseOptions applied to every line, AliasInput ignored as line buffer reused.
+type JStructNDJSONReader struct {
+	ctx  context.Context
+	rd   *bufio.Reader
+	opts ParseOptions
+	buf  []byte // the last line
+	pos  int    // offset of the next line
+	line int    // number of the last line
+}
+
+// NewJStructNDJSONReader creates reader of NDJSON values from rd
+func NewJStructNDJSONReader(rd io.Reader, opts ParseOptions) *JStructNDJSONReader {
+	return NewJStructNDJSONReaderContext(context.Background(), rd, opts)
+}
+
+// NewJStructNDJSONReaderContext creates reader of NDJSON values from rd aborting once ctx is done.
+// Context is checked before every line and read from rd, cancellation returned as ContextError with position in rd.
+func NewJStructNDJSONReaderContext(ctx context.Context, rd io.Reader, opts ParseOptions) *JStructNDJSONReader {
+	opts.AliasInput = false
+	return &JStructNDJSONReader{ctx: ctx, rd: bufio.NewReader(&contextReader{ctx: ctx, rd: rd}), opts: opts}
+}
+
+// Next parses the next value to v, returns io.EOF after the last one.
+// Invalid lines reported as NDJSONLineError, reading can be continued from the next line.
+func (r *JStructNDJSONReader) Next(v JStructOps) error {
+	for {
+		if e := r.ctx.Err(); e != nil {
+			return ContextError{Err: e, Pos: r.pos}
+		}
+		start := r.pos
+		b, e := r.readLine()
+		if e != nil && e != io.EOF {
+			if ce := r.ctx.Err(); ce != nil {
+				return ContextError{Err: ce, Pos: r.pos}
+			}
+			return e
+		}
+		if len(b) == 0 {
+			return io.EOF
+		}
+		r.line++
+		if isBlank(b) || r.opts.Relaxed && skipTrivia(b, 0) == len(b) {
+			// comments of relaxed mode take whole line
+			continue
+		}
+		if b[len(b)-1] != h.NewLineCh {
+			// root primitive should be followed by delimiter
+			r.buf = append(r.buf, h.NewLineCh)
+			b = r.buf
+		}
+		e = JStructParseBytesContext(r.ctx, b, v, r.opts)
+		if ce, ok := e.(ContextError); ok {
+			ce.Pos += start
+			return ce
+		}
+		if e != nil {
+			return NDJSONLineError{Line: r.line, Err: e}
+		}
+		return nil
+	}
+}
+
+// Line returns number of the last read line starting from 1
+func (r *JStructNDJSONReader) Line() int {
+	return r.line
+}
+
+// readLine reads the next line with its end to r.buf
+func (r *JStructNDJSONReader) readLine() ([]byte, error) {
+	r.buf = r.buf[:0]
+	for {
+		b, e := r.rd.ReadSlice(h.NewLineCh)
+		r.buf = append(r.buf, b...)
+		if e != bufio.ErrBufferFull {
+			r.pos += len(r.buf)
+			return r.buf, e
+		}
+	}
+}
+
+// isBlank reports whether b consists of JSON whitespaces only
+func isBlank(b []byte) bool {
+	for _, ch := range b {
+		if !h.SpaceCh[ch] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package JsonStruct
+
+import (
+	h "github.com/Pencroff/JsonStruct/helper"
+	"math"
+)
+
+// RoundingMode defines how fractional part of Float dropped in conversion to integer
+type RoundingMode byte
+
+const (
+	RoundTruncate RoundingMode = iota // toward zero, same as Int() and Uint()
+	RoundNearest                      // half away from zero, same as helper.FloatToInt
+	RoundHalfEven                     // half to even
+	RoundFloor                        // toward negative infinity
+	RoundCeil                         // toward positive infinity
+)
+
+func (m RoundingMode) round(f float64) float64 {
+	switch m {
+	case RoundNearest:
+		return math.Round(f)
+	case RoundHalfEven:
+		return math.RoundToEven(f)
+	case RoundFloor:
+		return math.Floor(f)
+	case RoundCeil:
+		return math.Ceil(f)
+	}
+	return math.Trunc(f)
+}
+
+// ToInt converts number v to int64 rounding floats by mode,
+// ok is false for values of other types and numbers out of range, the last saturate at MinInt or MaxInt
+func ToInt(v JStructOps, mode RoundingMode) (int64, bool) {
+	switch v.Type() {
+	case Int:
+		return v.Int(), true
+	case Uint:
+		return h.UintToInt(v.Uint())
+	case Float:
+		return h.FloatTruncToInt(mode.round(v.Float()))
+	}
+	return 0, false
+}
+
+// ToUint converts number v to uint64 rounding floats by mode,
+// ok is false for values of other types and numbers out of range, the last saturate at 0 or MaxUint
+func ToUint(v JStructOps, mode RoundingMode) (uint64, bool) {
+	switch v.Type() {
+	case Int:
+		return h.IntToUint(v.Int())
+	case Uint:
+		return v.Uint(), true
+	case Float:
+		return h.FloatTruncToUint(mode.round(v.Float()))
+	}
+	return 0, false
+}
+
+// ToFloat converts number v to float64,
+// ok is false for values of other types and integers beyond safe range as they may lose precision
+func ToFloat(v JStructOps) (float64, bool) {
+	switch v.Type() {
+	case Int:
+		n := v.Int()
+		return float64(n), n >= h.MinSafeInt && n <= h.MaxSafeInt
+	case Uint:
+		n := v.Uint()
+		return float64(n), n <= uint64(h.MaxSafeInt)
+	case Float:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// IsSafeInt reports whether v is integer number in range from MinSafeInt to MaxSafeInt,
+// same as Number.isSafeInteger in JavaScript
+func IsSafeInt(v JStructOps) bool {
+	if v.Type() == Float {
+		f := v.Float()
+		return f == math.Trunc(f) && math.Abs(f) <= float64(h.MaxSafeInt)
+	}
+	_, ok := ToFloat(v)
+	return ok
+}
@@ -2,6 +2,15 @@ package JsonStruct
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/base64"
+	h "github.com/Pencroff/JsonStruct/helper"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
 )
 
 func ParsePrimitiveValue(bt byte, rd *bufio.Reader, v JStructOps) (e error) {
@@ -23,3 +32,269 @@ func ParsePrimitiveValue(bt byte, rd *bufio.Reader, v JStructOps) (e error) {
 	}
 	return
 }
+
+// parseTokenValue sets value of tokenizer token to v,
+// alias keeps strings pointing to b
+func parseTokenValue(kind TokenizerKind, b []byte, v JStructOps, alias bool) error {
+	switch kind {
+	case KindNull:
+		v.SetNull()
+	case KindFalse:
+		v.SetBool(false)
+	case KindTrue:
+		v.SetBool(true)
+	case KindNumber:
+		parseNumber(b, v)
+	case KindFloatNumber:
+		// out of range values resolved as +/-Inf same as JSON.parse
+		f, e := strconv.ParseFloat(bytesToString(b), 64)
+		if e != nil && bytes.HasSuffix(b, h.NaNTokenData) {
+			// signed NaN of relaxed mode
+			f = math.NaN()
+		}
+		v.SetFloat(f)
+	case KindTime:
+		str := unquote(b)
+		tm, e := time.Parse(time.RFC3339, str)
+		if e != nil {
+			// valid format but out of range value, like leap second
+			v.SetString(str)
+			return nil
+		}
+		setParsedTime(v, tm, b)
+	case KindString:
+		v.SetString(unquoteAlias(b, alias))
+	default:
+		return InvalidJsonError{}
+	}
+	return nil
+}
+
+// setParsedTime sets time tm parsed from token b keeping its text if v supports TimeSourceOps
+func setParsedTime(v JStructOps, tm time.Time, b []byte) {
+	if tv, ok := v.(TimeSourceOps); ok {
+		tv.SetParsedTime(tm, string(b))
+		return
+	}
+	v.SetTime(tm)
+}
+
+// parseTimeLayouts sets time of quoted string b parsed by the first matching layout
+func parseTimeLayouts(b []byte, layouts []string, v JStructOps) bool {
+	str := unquote(b)
+	for _, layout := range layouts {
+		tm, e := time.Parse(layout, str)
+		if e == nil {
+			setParsedTime(v, tm, b)
+			return true
+		}
+	}
+	return false
+}
+
+// parseEpoch sets time of unix epoch number b, fraction of float number kept up to nanoseconds
+func parseEpoch(kind TokenizerKind, b []byte, unit EpochUnit, v JStructOps) bool {
+	var n JsonStruct
+	e := parseTokenValue(kind, b, &n, false)
+	if e != nil {
+		return false
+	}
+	scale := int64(time.Second)
+	if unit == EpochMillis {
+		scale = int64(time.Millisecond)
+	}
+	var tm time.Time
+	switch n.Type() {
+	case Int:
+		i := n.Int()
+		tm = time.Unix(i/(int64(time.Second)/scale), i%(int64(time.Second)/scale)*scale)
+	case Float:
+		f := n.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return false
+		}
+		sec, frac := math.Modf(f * float64(scale) / float64(time.Second))
+		if math.Abs(sec) >= 1<<62 {
+			return false
+		}
+		tm = time.Unix(int64(sec), int64(math.Round(frac*float64(time.Second))))
+	default:
+		return false
+	}
+	setParsedTime(v, tm.UTC(), b)
+	return true
+}
+
+// parseBinary sets bytes of base64 string token b
+func parseBinary(b []byte, v JStructOps) bool {
+	bv, ok := v.(BinaryOps)
+	if !ok {
+		return false
+	}
+	src := b[1 : len(b)-1]
+	if bytes.IndexByte(src, h.BackSlashCh) >= 0 {
+		src = []byte(unquote(b))
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding} {
+		dst := make([]byte, enc.DecodedLen(len(src)))
+		n, e := enc.Decode(dst, src)
+		if e == nil {
+			bv.SetBytes(dst[:n])
+			return true
+		}
+	}
+	return false
+}
+
+// parseNumber resolves integer number as Int, Uint or Float for values out of range
+func parseNumber(b []byte, v JStructOps) {
+	if b[0] == h.PlusCh {
+		b = b[1:]
+	}
+	if len(b) > 2 && (b[1] == 'x' || b[1] == 'X' || b[2] == 'x' || b[2] == 'X') {
+		parseHexNumber(b, v)
+		return
+	}
+	str := bytesToString(b)
+	if n, ok := h.StringToInt(str); ok {
+		v.SetInt(n)
+		return
+	}
+	if b[0] != h.MinusCh {
+		if n, ok := h.StringToUint(str); ok {
+			v.SetUint(n)
+			return
+		}
+	}
+	f, _ := strconv.ParseFloat(str, 64)
+	v.SetFloat(f)
+}
+
+// parseHexNumber resolves hex number of relaxed mode (`0x1F`, `-0x1F`) same as decimal one
+func parseHexNumber(b []byte, v JStructOps) {
+	neg := b[0] == h.MinusCh
+	if neg {
+		b = b[1:]
+	}
+	digits := bytesToString(b[2:])
+	n, e := strconv.ParseUint(digits, 16, 64)
+	switch {
+	case e != nil:
+		var f float64
+		for _, ch := range b[2:] {
+			f = f*16 + float64(hexToRune([]byte{ch}))
+		}
+		if neg {
+			f = -f
+		}
+		v.SetFloat(f)
+	case neg && n <= h.MinIntUint:
+		v.SetInt(-int64(n))
+	case neg:
+		v.SetFloat(-float64(n))
+	case n <= h.MaxIntUint:
+		v.SetInt(int64(n))
+	default:
+		v.SetUint(n)
+	}
+}
+
+// unquote returns content of quoted json string with resolved escape sequences,
+// string should be validated by tokenizer
+func unquote(b []byte) string {
+	b = b[1 : len(b)-1]
+	idx := bytes.IndexByte(b, h.BackSlashCh)
+	if idx == -1 {
+		return string(b)
+	}
+	res := make([]byte, idx, len(b))
+	copy(res, b)
+	return string(appendUnescaped(res, b[idx:]))
+}
+
+// appendUnescaped appends string content b with resolved escape sequences to res
+func appendUnescaped(res []byte, b []byte) []byte {
+	idx := 0
+	l := len(b)
+	for idx < l {
+		ch := b[idx]
+		if ch != h.BackSlashCh {
+			res = append(res, ch)
+			idx++
+			continue
+		}
+		idx++
+		switch b[idx] {
+		case 'b':
+			res = append(res, '\b')
+		case 'f':
+			res = append(res, '\f')
+		case 'n':
+			res = append(res, '\n')
+		case 'r':
+			res = append(res, '\r')
+		case 't':
+			res = append(res, '\t')
+		case 'v':
+			res = append(res, '\v')
+		case '0':
+			res = append(res, 0)
+		case 'x':
+			r := hexToRune(b[idx+1 : idx+3])
+			idx += 2
+			var buf [utf8.UTFMax]byte
+			n := utf8.EncodeRune(buf[:], r)
+			res = append(res, buf[:n]...)
+		case 'u':
+			r := hexToRune(b[idx+1 : idx+5])
+			idx += 4
+			if utf16.IsSurrogate(r) {
+				r2 := utf8.RuneError
+				if idx+6 < l && b[idx+1] == h.BackSlashCh && b[idx+2] == 'u' {
+					r2 = hexToRune(b[idx+3 : idx+7])
+				}
+				r = utf16.DecodeRune(r, r2)
+				if r != utf8.RuneError {
+					idx += 6
+				}
+			}
+			var buf [utf8.UTFMax]byte
+			n := utf8.EncodeRune(buf[:], r)
+			res = append(res, buf[:n]...)
+		default:
+			// '"', '\', '/' and any character escaping itself in relaxed mode
+			res = append(res, b[idx])
+		}
+		idx++
+	}
+	return res
+}
+
+// unquoteAlias works as unquote, but returns string pointing to b if alias allowed and no escape sequences
+func unquoteAlias(b []byte, alias bool) string {
+	if alias && bytes.IndexByte(b, h.BackSlashCh) == -1 {
+		return bytesToString(b[1 : len(b)-1])
+	}
+	return unquote(b)
+}
+
+func hexToRune(b []byte) rune {
+	var r rune
+	for _, ch := range b {
+		switch {
+		case ch >= '0' && ch <= '9':
+			ch -= '0'
+		case ch >= 'a' && ch <= 'f':
+			ch -= 'a' - 10
+		default:
+			ch -= 'A' - 10
+		}
+		r = r<<4 | rune(ch)
+	}
+	return r
+}
+
+// bytesToString converts without allocation, result valid while b is not changed
+func bytesToString(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
+}
@@ -0,0 +1,247 @@
+package JsonStruct
+
+import (
+	"encoding/base64"
+	h "github.com/Pencroff/JsonStruct/helper"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// sourceOps is implemented by values able to provide source text they parsed from,
+// source returns nil if value changed after parsing or its text is not strict JSON.
+// Source text written only with default options, it does not follow others.
+type sourceOps interface {
+	source() []byte
+}
+
+// appendJStruct appends compact JSON text of v with sorted object keys, nil values written as null
+func appendJStruct(dst []byte, v JStructOps, opts SerializeOptions) ([]byte, error) {
+	if v == nil {
+		return append(dst, h.NullTokenData...), nil
+	}
+	if sv, ok := v.(sourceOps); ok && opts == (SerializeOptions{}) {
+		if b := sv.source(); b != nil {
+			return append(dst, b...), nil
+		}
+	}
+	var e error
+	switch v.Type() {
+	case Object:
+		keys := v.Keys()
+		sort.Strings(keys)
+		dst = append(dst, h.OpenBraceCh)
+		for i, k := range keys {
+			if i > 0 {
+				dst = append(dst, h.CommaCh)
+			}
+			dst = appendEscaped(dst, k, opts.Escape)
+			dst = append(dst, h.ColonCh)
+			dst, e = appendJStruct(dst, v.GetKey(k), opts)
+			if e != nil {
+				return dst, e
+			}
+		}
+		return append(dst, h.CloseBraceCh), nil
+	case Array:
+		dst = append(dst, h.OpenBracketCh)
+		for i, l := 0, v.Size(); i < l; i++ {
+			if i > 0 {
+				dst = append(dst, h.CommaCh)
+			}
+			dst, e = appendJStruct(dst, v.GetIndex(i), opts)
+			if e != nil {
+				return dst, e
+			}
+		}
+		return append(dst, h.CloseBracketCh), nil
+	}
+	return appendPrimitive(dst, v, opts)
+}
+
+// appendPrimitive appends JSON text of primitive value v, containers written as null
+func appendPrimitive(dst []byte, v JStructOps, opts SerializeOptions) ([]byte, error) {
+	switch v.Type() {
+	case False:
+		return append(dst, "false"...), nil
+	case True:
+		return append(dst, "true"...), nil
+	case Int:
+		return strconv.AppendInt(dst, v.Int(), 10), nil
+	case Uint:
+		return strconv.AppendUint(dst, v.Uint(), 10), nil
+	case Float:
+		return appendNumber(dst, v.Float(), opts)
+	case String:
+		return appendEscaped(dst, v.String(), opts.Escape), nil
+	case Time:
+		if tv, ok := v.(TimeSourceOps); ok && opts.TimeLayout == "" && opts.TimeLocation == nil {
+			if raw := tv.TimeSource(); raw != "" {
+				return append(dst, raw...), nil
+			}
+		}
+		return appendTime(dst, v.Time(), opts), nil
+	case Binary:
+		b, _ := v.Value().([]byte)
+		return appendBase64(dst, b, opts), nil
+	}
+	return append(dst, "null"...), nil
+}
+
+// appendBase64 appends b as JSON string in standard or URL-safe base64
+func appendBase64(dst []byte, b []byte, opts SerializeOptions) []byte {
+	enc := base64.StdEncoding
+	if opts.BinaryURL {
+		enc = base64.URLEncoding
+	}
+	dst = append(dst, '"')
+	n := len(dst)
+	dst = append(dst, make([]byte, enc.EncodedLen(len(b)))...)
+	enc.Encode(dst[n:], b)
+	return append(dst, '"')
+}
+
+// appendTime appends t as JSON string formatted according to opts
+func appendTime(dst []byte, t time.Time, opts SerializeOptions) []byte {
+	if opts.TimeLocation != nil {
+		t = t.In(opts.TimeLocation)
+	}
+	if opts.TimeLayout != "" {
+		return appendEscaped(dst, t.Format(opts.TimeLayout), opts.Escape)
+	}
+	dst = append(dst, '"')
+	dst = t.AppendFormat(dst, time.RFC3339Nano)
+	return append(dst, '"')
+}
+
+// appendNumber appends f formatted according to opts
+func appendNumber(dst []byte, f float64, opts SerializeOptions) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		switch opts.NonFinite {
+		case NonFiniteNull:
+			return append(dst, h.NullTokenData...), nil
+		case NonFiniteString:
+			dst = append(dst, '"')
+			dst = appendFloat(dst, f)
+			return append(dst, '"'), nil
+		case NonFiniteLiteral:
+			return appendFloat(dst, f), nil
+		}
+		return dst, NonFiniteFloatError
+	}
+	switch opts.Float {
+	case FloatFixed:
+		return strconv.AppendFloat(dst, f, 'f', opts.floatPrecision(), 64), nil
+	case FloatExponent:
+		return strconv.AppendFloat(dst, f, 'e', opts.floatPrecision(), 64), nil
+	}
+	return appendFloat(dst, f), nil
+}
+
+// appendFloat appends shortest representation of f switching to exponent same as ES6 (and encoding/json),
+// NaN and Inf written as JSON5 literals
+func appendFloat(dst []byte, f float64) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(dst, "NaN"...)
+	case math.IsInf(f, 1):
+		return append(dst, "Infinity"...)
+	case math.IsInf(f, -1):
+		return append(dst, "-Infinity"...)
+	}
+	abs := math.Abs(f)
+	fmt := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		fmt = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, fmt, -1, 64)
+	if fmt == 'e' {
+		// clean up e-09 to e-9
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst
+}
+
+// appendQuoted appends s as quoted JSON string escaped same as encoding/json
+func appendQuoted(dst []byte, s string) []byte {
+	return appendEscaped(dst, s, EscapeHTML)
+}
+
+// appendEscaped appends s as quoted JSON string, quote, backslash and control characters always escaped,
+// other characters escaped according to mode
+func appendEscaped(dst []byte, s string, mode EscapeMode) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && (mode != EscapeHTML || b != '<' && b != '>' && b != '&') {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			default:
+				dst = appendUnicodeEscape(dst, rune(b))
+			}
+			i++
+			start = i
+			continue
+		}
+		if mode == EscapeRaw {
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1 && mode != EscapeASCII:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+		case mode == EscapeASCII:
+			dst = append(dst, s[start:i]...)
+			if r >= 0x10000 {
+				r1, r2 := utf16.EncodeRune(r)
+				dst = appendUnicodeEscape(dst, r1)
+				r = r2
+			}
+			dst = appendUnicodeEscape(dst, r)
+		case r == '\u2028' || r == '\u2029':
+			// line separators are not valid in JavaScript strings
+			dst = append(dst, s[start:i]...)
+			dst = appendUnicodeEscape(dst, r)
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// appendUnicodeEscape appends \uXXXX sequence of code unit r
+func appendUnicodeEscape(dst []byte, r rune) []byte {
+	return append(dst, '\\', 'u', hexDigits[r>>12&0xF], hexDigits[r>>8&0xF], hexDigits[r>>4&0xF], hexDigits[r&0xF])
+}
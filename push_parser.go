@@ -0,0 +1,222 @@
+package JsonStruct
+
+import (
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+)
+
+// JStructPushParser parses JSON data pushed by chunks.
+// It fits cases when data arrives as discrete frames (WebSocket messages, gRPC chunks)
+// and blocking io.Reader requires goroutine per connection.
+// Chunks can be split at any byte: inside of string, escape sequence or number.
+type JStructPushParser interface {
+	Write(p []byte) (int, error) // feed next chunk of data
+	Close() error                // signal end of data, validates the rest of document
+}
+
+// JStructPushHandler receives every token as soon as it is complete.
+// Value is valid only during the call, copy it to keep.
+type JStructPushHandler func(kind TokenizerKind, level TokenizerLevel, value []byte) error
+
+// NewJStructPushParser creates push parser emitting tokens to fn
+func NewJStructPushParser(fn JStructPushHandler) JStructPushParser {
+	return &JStructPushParserImpl{
+		fn: fn,
+		sc: JStructBytesScannerImpl{ptr: -1},
+		tk: JStructTokenizerImpl{scLevel: LevelRoot, depth: []TokenizerLevel{LevelRoot}, count: []int{0}},
+	}
+}
+
+// NewJStructValuePushParser creates push parser building v,
+// fn (optional) is called once root value is complete
+func NewJStructValuePushParser(v JStructOps, fn func(v JStructOps) error) JStructPushParser {
+	b := &jStructBuilder{root: v}
+	return NewJStructPushParser(func(kind TokenizerKind, level TokenizerLevel, value []byte) error {
+		e := b.add(kind, level, value)
+		if e == nil && b.done && fn != nil {
+			e = fn(v)
+		}
+		return e
+	})
+}
+
+// JStructPushParserImpl keeps incomplete tail of data and tokenizer state between chunks.
+// Incomplete token tokenized again from its beginning once it can be completed,
+// chunks of long string or number only scanned for the end of token.
+type JStructPushParserImpl struct {
+	fn     JStructPushHandler
+	sc     JStructBytesScannerImpl
+	tk     JStructTokenizerImpl
+	depth  []TokenizerLevel // tokenizer depth snapshot
+	count  []int            // tokenizer count snapshot
+	buf    []byte           // incomplete tail of data
+	off    int              // position of buf in the stream
+	pend   pushPending
+	closed bool
+	err    error
+}
+
+type pendingKind byte
+
+const (
+	pendingNone   pendingKind = iota // tokenizer called for every chunk
+	pendingString                    // inside of string
+	pendingNumber                    // inside of number
+	pendingEnd                       // token complete, tokenizer waits for the next character
+)
+
+// pushPending keeps scanning state of incomplete token at the beginning of buffer
+type pushPending struct {
+	kind pendingKind
+	pos  int  // scanned bytes of buffer
+	esc  bool // the last scanned byte is backslash of escape sequence
+}
+
+func (p *JStructPushParserImpl) Write(b []byte) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	if p.closed {
+		return 0, ClosedPushParserError
+	}
+	p.buf = append(p.buf, b...)
+	if !p.pend.ready(p.buf) {
+		return len(b), nil
+	}
+	p.err = p.parse()
+	return len(b), p.err
+}
+
+func (p *JStructPushParserImpl) Close() error {
+	if p.closed {
+		return p.err
+	}
+	p.closed = true
+	if p.err == nil {
+		p.err = p.parse()
+	}
+	return p.err
+}
+
+// parse tokenizes buffered data till the end of data or document
+func (p *JStructPushParserImpl) parse() error {
+	sc := &p.sc
+	sc.ResetBytes(p.buf)
+	p.tk.sc = sc
+	consumed := 0
+	for {
+		state := p.tk
+		p.depth = append(p.depth[:0], p.tk.depth...)
+		p.count = append(p.count[:0], p.tk.count...)
+		e := p.tk.Next()
+		if sc.finished && !p.closed {
+			// data is over in the middle of token, wait for next chunk
+			p.tk = state
+			p.tk.depth = append(p.tk.depth[:0], p.depth...)
+			p.tk.count = append(p.tk.count[:0], p.count...)
+			break
+		}
+		if e == io.EOF {
+			return nil
+		}
+		if e != nil {
+			return p.shiftErr(e)
+		}
+		e = p.fn(p.tk.Kind(), p.tk.Level(), p.tk.Value())
+		if e != nil {
+			return e
+		}
+		consumed = sc.Index() + 1
+	}
+	p.tk.sc = nil
+	p.tk.v = nil
+	p.off += consumed
+	p.buf = append(p.buf[:0], p.buf[consumed:]...)
+	p.pend.track(p.buf)
+	return nil
+}
+
+// track finds incomplete string or number at the beginning of buf
+func (t *pushPending) track(buf []byte) {
+	i := 0
+	for i < len(buf) && (h.SpaceCh[buf[i]] || buf[i] == h.CommaCh || buf[i] == h.ColonCh) {
+		i++
+	}
+	*t = pushPending{pos: i + 1}
+	if i == len(buf) {
+		return
+	}
+	switch ch := buf[i]; {
+	case ch == h.QuoteCh:
+		t.kind = pendingString
+	case ch == h.MinusCh || h.NumCh[ch]:
+		t.kind = pendingNumber
+	default:
+		return
+	}
+	if t.ready(buf) && t.kind == pendingNone {
+		// token complete, the rest is whitespaces
+		t.kind = pendingEnd
+	}
+}
+
+// ready scans new bytes of buf and reports whether tokenizer can make progress on them:
+// token or escape sequence completed, invalid character found
+func (t *pushPending) ready(buf []byte) bool {
+	for ; t.pos < len(buf); t.pos++ {
+		ch := buf[t.pos]
+		switch t.kind {
+		case pendingString:
+			switch {
+			case t.esc:
+				t.esc = false
+				switch ch {
+				case h.QuoteCh, h.BackSlashCh, '/', 'b', 'f', 'n', 'r', 't', 'u':
+				default:
+					return true
+				}
+			case ch == h.BackSlashCh:
+				t.esc = true
+			case ch == h.QuoteCh:
+				t.pos++
+				t.kind = pendingNone
+				return true
+			case ch < 0x20:
+				return true
+			}
+		case pendingNumber:
+			if !h.NumCh[ch] && ch != h.PointCh && ch != h.ExpCh && ch != h.ExpSmCh && ch != h.PlusCh && ch != h.MinusCh {
+				t.kind = pendingNone
+				return true
+			}
+		case pendingEnd:
+			if !h.SpaceCh[ch] {
+				t.kind = pendingNone
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return t.kind == pendingNone
+}
+
+// shiftErr moves error position from buffer to stream
+func (p *JStructPushParserImpl) shiftErr(e error) error {
+	switch err := e.(type) {
+	case InvalidJsonPtrError:
+		err.Pos += p.off
+		return err
+	case InvalidJsonTokenPtrError:
+		err.Pos += p.off
+		return err
+	case LimitExceededError:
+		err.Pos += p.off
+		return err
+	case InvalidJsonError:
+		if p.off > 0 {
+			return InvalidJsonPtrError{Err: err.Err, Pos: p.off - 1}
+		}
+	}
+	return e
+}
@@ -0,0 +1,449 @@
+package JsonStruct
+
+import (
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+	"sort"
+)
+
+// RepairKind describes change made by recovery parser
+type RepairKind byte
+
+const (
+	RepairUnknown        RepairKind = iota
+	RepairCloseString               // closing quote added to unterminated string
+	RepairCloseContainer            // closing bracket added to unclosed array or object
+	RepairInsertComma               // comma added between values
+	RepairInsertColon               // colon added after key
+	RepairInsertValue               // null added as missing value
+	RepairQuoteWord                 // bare word quoted as string
+	RepairEscapeChar                // control character escaped or invalid escape sequence dropped
+	RepairDropToken                 // unexpected text dropped
+	RepairInsertZero                // zero added before leading decimal point of number
+)
+
+func (k RepairKind) String() string {
+	switch k {
+	case RepairCloseString:
+		return "RepairCloseString"
+	case RepairCloseContainer:
+		return "RepairCloseContainer"
+	case RepairInsertComma:
+		return "RepairInsertComma"
+	case RepairInsertColon:
+		return "RepairInsertColon"
+	case RepairInsertValue:
+		return "RepairInsertValue"
+	case RepairQuoteWord:
+		return "RepairQuoteWord"
+	case RepairEscapeChar:
+		return "RepairEscapeChar"
+	case RepairDropToken:
+		return "RepairDropToken"
+	case RepairInsertZero:
+		return "RepairInsertZero"
+	default:
+		return "RepairUnknown"
+	}
+}
+
+// Repair is change applied to input by recovery parser
+type Repair struct {
+	Kind RepairKind
+	Pos  int    // offset in input, text inserted before it or dropped from it
+	Text string // inserted or dropped text, original word for RepairQuoteWord
+}
+
+// JStructParseRecover parses malformed JSON (truncated, generated by LLM, etc.) fixing problems on the way:
+// unterminated strings, arrays and objects closed at the end of input, missing commas, colons and values inserted,
+// bare words quoted, malformed numbers cut at the first invalid character, stray tokens dropped.
+// It returns the list of applied repairs.
+// Input repaired as standard JSON, opts.Relaxed ignored. Error returned if input has no value
+// or repaired text still invalid, positions of such error and opts.Spans refer to repaired text.
+func JStructParseRecover(b []byte, v JStructOps, opts ParseOptions) ([]Repair, error) {
+	r := jStructRepairer{in: b, out: make([]byte, 0, len(b)+8), comma: -1, commaRepair: -1}
+	e := r.repair()
+	if e != nil {
+		return r.repairs, e
+	}
+	opts.Relaxed = false
+	return r.repairs, JStructParseBytesWithOptions(r.out, v, opts)
+}
+
+type repairState byte
+
+const (
+	repairValue     repairState = iota // value expected
+	repairKey                          // key or end of object expected
+	repairColon                        // colon after key expected
+	repairDelimiter                    // comma or end of container expected
+	repairDone                         // root value completed
+)
+
+// jStructRepairer copies input to out fixing syntax errors
+type jStructRepairer struct {
+	in      []byte
+	out     []byte
+	pos     int // position in input
+	repairs []Repair
+	stack   []byte // closing brackets of open containers
+	state   repairState
+	comma   int // index of the last comma in out, -1 if something follows it
+	//=======
+	commaPos    int // position of the last comma in input
+	commaRepair int // index of repair inserted the last comma, -1 if comma copied from input
+}
+
+func (r *jStructRepairer) repair() error {
+	for r.pos < len(r.in) {
+		ch := r.in[r.pos]
+		if h.SpaceCh[ch] {
+			r.out = append(r.out, ch)
+			r.pos++
+			continue
+		}
+		r.token(ch)
+	}
+	if r.state == repairValue && len(r.stack) == 0 {
+		return InvalidJsonError{Err: io.EOF}
+	}
+	r.complete()
+	for len(r.stack) > 0 {
+		r.closeTop()
+	}
+	// trailing comma dropped after repairs following it
+	sort.SliceStable(r.repairs, func(i, j int) bool {
+		return r.repairs[i].Pos < r.repairs[j].Pos
+	})
+	return nil
+}
+
+func (r *jStructRepairer) token(ch byte) {
+	switch r.state {
+	case repairDone:
+		r.drop(len(r.in) - r.pos)
+	case repairKey:
+		r.key(ch)
+	case repairColon:
+		switch ch {
+		case h.ColonCh:
+			r.emit(1)
+			r.state = repairValue
+		case h.CommaCh, h.CloseBraceCh, h.CloseBracketCh:
+			// key without value
+			r.complete()
+			r.delimiter(ch)
+		default:
+			r.insertAfterToken(RepairInsertColon, ":")
+			r.state = repairValue
+			r.value(ch)
+		}
+	case repairDelimiter:
+		r.delimiter(ch)
+	default:
+		r.value(ch)
+	}
+}
+
+func (r *jStructRepairer) value(ch byte) {
+	switch {
+	case ch == h.OpenBracketCh:
+		r.open(h.CloseBracketCh, repairValue)
+	case ch == h.OpenBraceCh:
+		r.open(h.CloseBraceCh, repairKey)
+	case ch == h.QuoteCh:
+		r.readString()
+		r.valueEnd()
+	case ch == h.MinusCh || h.NumCh[ch] || r.leadingPoint():
+		r.readNumber()
+	case ch == h.CloseBracketCh || ch == h.CloseBraceCh:
+		r.close(ch)
+	case h.IdentCh[ch]:
+		r.readWord(false)
+		r.valueEnd()
+	case ch == h.CommaCh && r.comma == -1 && r.lastByte() == h.ColonCh:
+		// missing value of key
+		r.complete()
+		r.delimiter(ch)
+	default:
+		r.drop(1)
+	}
+}
+
+func (r *jStructRepairer) key(ch byte) {
+	switch {
+	case ch == h.QuoteCh:
+		r.readString()
+		r.state, r.comma = repairColon, -1
+	case ch == h.CloseBracketCh || ch == h.CloseBraceCh:
+		r.close(ch)
+	case h.IdentCh[ch] || ch == h.MinusCh:
+		r.readWord(true)
+		r.state, r.comma = repairColon, -1
+	default:
+		r.drop(1)
+	}
+}
+
+func (r *jStructRepairer) delimiter(ch byte) {
+	switch {
+	case ch == h.CommaCh:
+		r.comma, r.commaPos, r.commaRepair = len(r.out), r.pos, -1
+		r.emit(1)
+		r.state = r.itemState()
+		return
+	case ch == h.CloseBracketCh || ch == h.CloseBraceCh:
+		r.close(ch)
+	case ch == h.QuoteCh || ch == h.OpenBracketCh || ch == h.OpenBraceCh || ch == h.MinusCh || h.IdentCh[ch] || r.leadingPoint():
+		// comma tracked as trailing one till the next item starts, next token may be dropped
+		r.commaRepair = len(r.repairs)
+		r.comma = r.insertAfterToken(RepairInsertComma, ",")
+		r.commaPos = r.repairs[r.commaRepair].Pos
+		r.state = r.itemState()
+		r.token(ch)
+	default:
+		r.drop(1)
+	}
+}
+
+// itemState returns state of the next item in current container
+func (r *jStructRepairer) itemState() repairState {
+	if r.stack[len(r.stack)-1] == h.CloseBraceCh {
+		return repairKey
+	}
+	return repairValue
+}
+
+func (r *jStructRepairer) valueEnd() {
+	r.comma = -1
+	if len(r.stack) == 0 {
+		r.state = repairDone
+		return
+	}
+	r.state = repairDelimiter
+}
+
+func (r *jStructRepairer) open(closing byte, state repairState) {
+	r.emit(1)
+	r.stack = append(r.stack, closing)
+	r.state = state
+	r.comma = -1
+}
+
+// close closes containers till the one matching ch, ch dropped if there is no such container
+func (r *jStructRepairer) close(ch byte) {
+	idx := len(r.stack) - 1
+	for idx >= 0 && r.stack[idx] != ch {
+		idx--
+	}
+	if idx < 0 {
+		r.drop(1)
+		return
+	}
+	r.complete()
+	for len(r.stack)-1 > idx {
+		r.closeTop()
+	}
+	r.emit(1)
+	r.stack = r.stack[:idx]
+	r.valueEnd()
+}
+
+// closeTop adds missing closing bracket of current container
+func (r *jStructRepairer) closeTop() {
+	l := len(r.stack) - 1
+	r.insert(RepairCloseContainer, string(r.stack[l]))
+	r.stack = r.stack[:l]
+	r.valueEnd()
+}
+
+// complete finishes current item of container before its end
+func (r *jStructRepairer) complete() {
+	switch r.state {
+	case repairColon:
+		r.insert(RepairInsertColon, ":")
+		r.insert(RepairInsertValue, "null")
+	case repairValue:
+		if r.comma == -1 && len(r.out) > 0 && r.lastByte() == h.ColonCh {
+			r.insert(RepairInsertValue, "null")
+		}
+	}
+	if r.comma != -1 {
+		// trailing comma
+		r.out = append(r.out[:r.comma], r.out[r.comma+1:]...)
+		if r.commaRepair != -1 {
+			r.repairs = append(r.repairs[:r.commaRepair], r.repairs[r.commaRepair+1:]...)
+		} else {
+			r.repairs = append(r.repairs, Repair{Kind: RepairDropToken, Pos: r.commaPos, Text: ","})
+		}
+		r.comma, r.commaRepair = -1, -1
+	}
+	r.state = repairDelimiter
+}
+
+// lastByte returns the last byte of out except whitespaces
+func (r *jStructRepairer) lastByte() byte {
+	for i := len(r.out) - 1; i >= 0; i-- {
+		if !h.SpaceCh[r.out[i]] {
+			return r.out[i]
+		}
+	}
+	return 0
+}
+
+func (r *jStructRepairer) readString() {
+	r.emit(1)
+	for r.pos < len(r.in) {
+		ch := r.in[r.pos]
+		switch {
+		case ch == h.QuoteCh:
+			r.emit(1)
+			return
+		case ch == h.BackSlashCh:
+			r.readEscape()
+		case ch < 0x20:
+			esc := string(appendQuoted(nil, string(ch)))
+			esc = esc[1 : len(esc)-1]
+			r.repairs = append(r.repairs, Repair{Kind: RepairEscapeChar, Pos: r.pos, Text: esc})
+			r.out = append(r.out, esc...)
+			r.pos++
+		default:
+			r.emit(1)
+		}
+	}
+	r.insert(RepairCloseString, `"`)
+}
+
+// readEscape copies valid escape sequence or drops backslash of invalid one
+func (r *jStructRepairer) readEscape() {
+	rest := r.in[r.pos+1:]
+	if len(rest) > 0 {
+		switch rest[0] {
+		case h.QuoteCh, h.BackSlashCh, '/', 'b', 'f', 'n', 'r', 't':
+			r.emit(2)
+			return
+		case 'u':
+			if len(rest) >= 5 && h.HexCh[rest[1]] && h.HexCh[rest[2]] && h.HexCh[rest[3]] && h.HexCh[rest[4]] {
+				r.emit(6)
+				return
+			}
+		}
+	}
+	r.repairs = append(r.repairs, Repair{Kind: RepairEscapeChar, Pos: r.pos, Text: `\`})
+	r.pos++
+}
+
+// leadingPoint reports decimal point followed by digit at current position
+func (r *jStructRepairer) leadingPoint() bool {
+	return r.pos+1 < len(r.in) && r.in[r.pos] == h.PointCh && h.NumCh[r.in[r.pos+1]]
+}
+
+// readNumber copies number valid by JSON grammar, zero added before leading decimal point.
+// Number cut at the first invalid character, the rest of number characters dropped
+// (incomplete fraction or exponent of truncated number, extra point, sign or leading zero digits).
+func (r *jStructRepairer) readNumber() {
+	in := r.in
+	end := r.pos + 1
+	for end < len(in) && (h.NumCh[in[end]] || in[end] == h.PointCh || in[end] == h.ExpCh ||
+		in[end] == h.ExpSmCh || in[end] == h.PlusCh || in[end] == h.MinusCh) {
+		end++
+	}
+	i := r.pos
+	if in[i] == h.MinusCh {
+		i++
+	}
+	sign := i - r.pos
+	zero := i+1 < end && in[i] == h.PointCh && h.NumCh[in[i+1]]
+	switch {
+	case zero:
+	case i < end && in[i] == '0':
+		i++
+	case i < end && h.NumCh[in[i]]:
+		for i < end && h.NumCh[in[i]] {
+			i++
+		}
+	default:
+		// sign without digits, the rest read as next token
+		r.drop(1)
+		return
+	}
+	if i+1 < end && in[i] == h.PointCh && h.NumCh[in[i+1]] {
+		i += 2
+		for i < end && h.NumCh[in[i]] {
+			i++
+		}
+	}
+	if i+1 < end && (in[i] == h.ExpCh || in[i] == h.ExpSmCh) {
+		j := i + 1
+		if in[j] == h.PlusCh || in[j] == h.MinusCh {
+			j++
+		}
+		if j < end && h.NumCh[in[j]] {
+			for j < end && h.NumCh[in[j]] {
+				j++
+			}
+			i = j
+		}
+	}
+	if zero {
+		r.emit(sign)
+		r.insert(RepairInsertZero, "0")
+	}
+	r.emit(i - r.pos)
+	if i < end {
+		r.drop(end - i)
+	}
+	r.valueEnd()
+}
+
+// readWord copies true, false or null, other words quoted
+func (r *jStructRepairer) readWord(key bool) {
+	end := r.pos + 1
+	for end < len(r.in) && (h.IdentCh[r.in[end]] || r.in[end] == h.MinusCh || r.in[end] == h.PointCh) {
+		end++
+	}
+	word := string(r.in[r.pos:end])
+	if !key && (word == "true" || word == "false" || word == "null") {
+		r.emit(end - r.pos)
+		return
+	}
+	r.repairs = append(r.repairs, Repair{Kind: RepairQuoteWord, Pos: r.pos, Text: word})
+	r.out = appendQuoted(r.out, word)
+	r.pos = end
+}
+
+// emit copies n bytes of input
+func (r *jStructRepairer) emit(n int) {
+	r.out = append(r.out, r.in[r.pos:r.pos+n]...)
+	r.pos += n
+}
+
+func (r *jStructRepairer) insert(kind RepairKind, text string) {
+	r.repairs = append(r.repairs, Repair{Kind: kind, Pos: r.pos, Text: text})
+	r.out = append(r.out, text...)
+}
+
+// insertAfterToken inserts text before whitespaces following the last token, returns index of text in out
+func (r *jStructRepairer) insertAfterToken(kind RepairKind, text string) int {
+	idx := len(r.out)
+	for idx > 0 && h.SpaceCh[r.out[idx-1]] {
+		idx--
+	}
+	// whitespaces copied from input as is
+	pos := r.pos - (len(r.out) - idx)
+	r.repairs = append(r.repairs, Repair{Kind: kind, Pos: pos, Text: text})
+	r.out = append(r.out[:idx], append([]byte(text), r.out[idx:]...)...)
+	return idx
+}
+
+// drop skips n bytes of input, adjacent dropped text joined to one repair
+func (r *jStructRepairer) drop(n int) {
+	text := string(r.in[r.pos : r.pos+n])
+	l := len(r.repairs) - 1
+	if l >= 0 && r.repairs[l].Kind == RepairDropToken && r.repairs[l].Pos+len(r.repairs[l].Text) == r.pos {
+		r.repairs[l].Text += text
+	} else {
+		r.repairs = append(r.repairs, Repair{Kind: RepairDropToken, Pos: r.pos, Text: text})
+	}
+	r.pos += n
+}
@@ -8,6 +8,8 @@ var (
 	// Buffers
 	JStructScannerBufferSize       = 4 * 1024
 	JSStructScannerBufferThreshold = JStructScannerBufferSize >> 3
+	// JStructScannerPoolBufferLimit is max buffer size kept by pool of JStructParse, larger buffers are dropped
+	JStructScannerPoolBufferLimit = 64 * 1024
 )
 
 type JStructScanner interface {
@@ -20,6 +22,7 @@ type JStructScanner interface {
 	Buffer() []byte                    // current buffer
 	Window() (int, int)                // window of current buffer (start, end)
 	FillBuffFrom(idx int) (int, error) // fill buffer from idx, should be in interval [start, size)
+	Reset(rd io.Reader)                // start scanning of new data, buffer reused
 }
 
 func NewJStructScanner(rd io.Reader) JStructScanner {
@@ -53,6 +56,18 @@ type JStructScannerImpl struct {
 	released bool
 }
 
+// Reset starts scanning of rd keeping allocated buffer
+func (j *JStructScannerImpl) Reset(rd io.Reader) {
+	j.rd = rd
+	j.buf = j.buf[:cap(j.buf)]
+	j.size = len(j.buf)
+	j.start = 0
+	j.ptr = -1
+	j.idx = -1
+	j.finished = false
+	j.released = false
+}
+
 // Buffer returns current buffer
 func (j *JStructScannerImpl) Buffer() []byte {
 	return j.buf
@@ -104,6 +119,9 @@ func (j *JStructScannerImpl) Scan(n int) error {
 			n -= rn
 			j.ptr = rn - 1
 			j.idx = j.ptr
+			if e != nil && e != io.EOF {
+				return e
+			}
 		}
 	}
 
@@ -136,8 +154,10 @@ firstLoop:
 	// Outside of tail
 	// but still might in buffer
 
-	// move to front data if not there
-	if j.start != 0 {
+	// move to front data if not there,
+	// released head becomes free space
+	head := j.start
+	if head != 0 {
 		j.moveDataToFront()
 		// update idx by moved values
 		idx = j.ptr + 1
@@ -146,7 +166,7 @@ firstLoop:
 	// expand by default,
 	// skip if free space larger than
 	// threshold or offset value
-	availableSpace := tailSpace + j.start // tail + head
+	availableSpace := tailSpace + head
 	if availableSpace < max(n, j.threshold) {
 		j.expand()
 	}
@@ -160,6 +180,10 @@ firstLoop:
 		if j.finished {
 			return io.EOF
 		}
+		// reader failed, data read so far is available
+		if e != nil {
+			return e
+		}
 	}
 
 	goto loop
@@ -0,0 +1,27 @@
+package JsonStruct
+
+import "strconv"
+
+// Position is location in source text
+type Position struct {
+	Offset int // byte offset
+	Line   int // 1-based line number
+	Column int // 1-based column in bytes
+}
+
+func (p Position) String() string {
+	return strconv.Itoa(p.Line) + ":" + strconv.Itoa(p.Column)
+}
+
+// Span is location of value in source text, End points to the byte after value
+type Span struct {
+	Start Position
+	End   Position
+}
+
+func (s Span) String() string {
+	return s.Start.String() + "-" + s.End.String()
+}
+
+// SourceSpans maps parsed nodes to their location in source text, see ParseOptions.Spans
+type SourceSpans map[JStructOps]Span
@@ -2,7 +2,10 @@ package JsonStruct
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"sync"
+	"time"
 )
 
 // Injection inspired by github.com/Rhymond/go-money
@@ -18,6 +21,8 @@ var (
 	MarshalJSON = MarshalJSONFn
 	// JStructParse Func provides io.Reader based parsing of JSON data
 	JStructParse = JStructParseFn
+	// JStructParseBytes Func provides parsing of JSON data already in memory
+	JStructParseBytes = JStructParseBytesFn
 	// JStructSerialize Func provides io.Writer based serialization of JSON data
 	JStructSerialize = JStructSerializeFn
 )
@@ -37,29 +42,161 @@ var (
 // Initial implementation of the JSON parser supported Standard ECMA-404 JSON format.
 // https://www.ecma-international.org/publications-and-standards/standards/ecma-404/
 
+// ParseOptions customize parsing, zero value of limit means unlimited
+type ParseOptions struct {
+	// AliasInput keeps parsed strings without escape sequences pointing to input data instead of copy.
+	// Applicable to in-memory parsing only, input must not be changed while v is in use.
+	AliasInput bool
+
+	MaxDepth         int // max nesting of arrays and objects
+	MaxBytes         int // max size of document including whitespaces
+	MaxStringLength  int // max length of string or key in bytes of JSON text, quotes excluded
+	MaxNumberLength  int // max length of number in bytes of JSON text
+	MaxKeysPerObject int
+	MaxArrayLength   int
+
+	DuplicateKeys DuplicateKeyPolicy // resolution of repeated keys in object
+
+	// Relaxed accepts JSON5 / JSONC syntax: comments, trailing commas, single quoted strings,
+	// unquoted keys, hex numbers, leading plus, leading or trailing decimal point, Infinity and NaN
+	Relaxed bool
+
+	// Spans, if not nil, receives location of every node created by parser
+	Spans SourceSpans
+
+	// Strings in RFC3339 format resolved as Time unless NoTimeDetection set.
+	// TimeLayouts and TimeEpochKeys applied by tree parsers only, every string tried against each layout.
+	NoTimeDetection bool
+	TimeLayouts     []string             // additional layouts of strings resolved as Time, like TimeLayoutDate or time.RFC1123
+	TimeEpochKeys   map[string]EpochUnit // numbers of these object keys resolved as Time in UTC
+
+	// BinaryPaths lists JSON Pointers (RFC 6901) of strings decoded from base64 as Binary by tree parsers,
+	// standard and URL-safe alphabets accepted, invalid data kept as string
+	BinaryPaths []string
+}
+
+// TimeLayoutDate is RFC3339 full-date layout
+const TimeLayoutDate = "2006-01-02"
+
+// EpochUnit defines unit of unix time numbers
+type EpochUnit byte
+
+const (
+	EpochSeconds EpochUnit = iota
+	EpochMillis
+)
+
+// DuplicateKeyPolicy defines how parser resolves repeated keys in object
+type DuplicateKeyPolicy byte
+
+const (
+	DuplicateKeysLastWins       DuplicateKeyPolicy = iota // later value replaces earlier one
+	DuplicateKeysFirstWins                                // later values skipped
+	DuplicateKeysError                                    // parsing fails with DuplicateKeyError
+	DuplicateKeysCollectAsArray                           // all values collected to array in order of appearance
+)
+
+// limited reports if any of limits set
+func (o ParseOptions) limited() bool {
+	return o.MaxDepth > 0 || o.MaxBytes > 0 || o.MaxStringLength > 0 || o.MaxNumberLength > 0 ||
+		o.MaxKeysPerObject > 0 || o.MaxArrayLength > 0
+}
+
 func UnmarshalJSONFn(b []byte, v JStructOps) error {
-	var rd = bytes.NewReader(b)
-	return JStructParse(rd, v)
+	return JStructParseBytes(b, v)
 }
 
 func JStructParseFn(rd io.Reader, v JStructOps) (e error) {
-	sc := NewJStructScanner(rd)
-	tc := NewJStructTokenizer(sc)
-	e = tc.Next()
-	if e != nil {
+	return JStructParseWithOptions(rd, v, ParseOptions{})
+}
+
+// JStructParseWithOptions parses data from rd with limits of opts
+func JStructParseWithOptions(rd io.Reader, v JStructOps, opts ParseOptions) (e error) {
+	tc := acquireTokenizer(rd, opts)
+	e = parseTokens(tc, v, false, opts)
+	releaseTokenizer(tc)
+	return
+}
+
+// tokenizerPool keeps tokenizers with reader scanners between JStructParse calls
+var tokenizerPool = sync.Pool{
+	New: func() interface{} {
+		return NewJStructTokenizer(NewJStructScanner(nil))
+	},
+}
+
+func acquireTokenizer(rd io.Reader, opts ParseOptions) *JStructTokenizerImpl {
+	tc := tokenizerPool.Get().(*JStructTokenizerImpl)
+	tc.Reset(rd)
+	tc.SetOptions(opts)
+	return tc
+}
+
+// releaseTokenizer returns tokenizer to pool unless its buffer expanded over JStructScannerPoolBufferLimit
+func releaseTokenizer(tc *JStructTokenizerImpl) {
+	sc := tc.sc.(*JStructScannerImpl)
+	if cap(sc.buf) > JStructScannerPoolBufferLimit {
 		return
 	}
-	switch tc.Kind() {
-	case KindNull:
-		v.SetNull()
-	case KindTrue:
-		v.SetBool(true)
-	case KindFalse:
-		v.SetBool(false)
-	default:
-		e = InvalidJsonError{}
+	tc.Reset(nil)
+	tc.SetOptions(ParseOptions{})
+	tokenizerPool.Put(tc)
+}
+
+func JStructParseBytesFn(b []byte, v JStructOps) error {
+	return JStructParseBytesWithOptions(b, v, ParseOptions{})
+}
+
+// JStructParseBytesWithOptions parses in-memory data without copying it into scanner buffer
+func JStructParseBytesWithOptions(b []byte, v JStructOps, opts ParseOptions) error {
+	sc := NewJStructBytesScanner(b)
+	tc := NewJStructTokenizerWithOptions(sc, opts)
+	return parseTokens(tc, v, opts.AliasInput, opts)
+}
+
+// parseTokens builds v from tokens till the end of root value,
+// alias allowed only for tokens pointing to immutable data
+func parseTokens(tc JStructTokenizer, v JStructOps, alias bool, opts ParseOptions) error {
+	return parseTokensContext(context.Background(), tc, v, alias, opts)
+}
+
+// contextCheckTokens is number of tokens parsed between checks of context
+const contextCheckTokens = 1024
+
+// parseTokensContext works as parseTokens and returns ctx.Err() once ctx is done
+func parseTokensContext(ctx context.Context, tc JStructTokenizer, v JStructOps, alias bool, opts ParseOptions) error {
+	b := jStructBuilder{root: v, alias: alias, dup: opts.DuplicateKeys, layouts: opts.TimeLayouts, epoch: opts.TimeEpochKeys}
+	if len(opts.BinaryPaths) > 0 {
+		b.binary = make(map[string]bool, len(opts.BinaryPaths))
+		for _, p := range opts.BinaryPaths {
+			b.binary[p] = true
+		}
+	}
+	if t, ok := tc.(*JStructTokenizerImpl); ok {
+		b.pos = t.Start
+		if opts.Spans != nil {
+			b.spans, b.tk = opts.Spans, t
+		}
+	}
+	done := ctx.Done()
+	for n := 1; ; n++ {
+		if done != nil && n%contextCheckTokens == 0 {
+			if e := ctx.Err(); e != nil {
+				return e
+			}
+		}
+		e := tc.Next()
+		if e == io.EOF && b.done {
+			return nil
+		}
+		if e != nil {
+			return e
+		}
+		e = b.add(tc.Kind(), tc.Level(), tc.Value())
+		if e != nil {
+			return e
+		}
 	}
-	return
 }
 
 func MarshalJSONFn(v JStructOps) ([]byte, error) {
@@ -68,8 +205,72 @@ func MarshalJSONFn(v JStructOps) ([]byte, error) {
 	return b.Bytes(), err
 }
 
-func JStructSerializeFn(v JStructOps, wr io.Writer) (e error) {
-	return
+// SerializeOptions customize serialization, zero value matches encoding/json output
+type SerializeOptions struct {
+	Escape EscapeMode // escaping of strings and keys
+
+	// Time values written in original text if parsed, otherwise in RFC3339 with nanoseconds.
+	// Any of options below makes all Time values formatted.
+	TimeLayout   string         // layout of Time values
+	TimeLocation *time.Location // location of Time values
+
+	BinaryURL bool // Binary values written in URL-safe base64 instead of standard one
+
+	Float             FloatFormat     // formatting of Float values
+	FloatPrecision    int             // digits after decimal point for FloatFixed and FloatExponent, 0 or negative means as many as required
+	FloatPrecisionSet bool            // FloatPrecision 0 applied as is, numbers written without fraction
+	NonFinite         NonFinitePolicy // output of NaN and Infinity not supported by JSON
+}
+
+// floatPrecision returns precision of FloatFixed and FloatExponent output, -1 for shortest round-trip one
+func (o SerializeOptions) floatPrecision() int {
+	if o.FloatPrecision < 0 || o.FloatPrecision == 0 && !o.FloatPrecisionSet {
+		return -1
+	}
+	return o.FloatPrecision
+}
+
+// EscapeMode defines which characters of strings escaped in addition to quote, backslash and control characters
+type EscapeMode byte
+
+const (
+	EscapeHTML  EscapeMode = iota // <, >, &, U+2028 and U+2029 escaped, invalid UTF-8 replaced by U+FFFD, same as encoding/json
+	EscapeASCII                   // every non-ASCII character escaped, astral ones as surrogate pair, invalid UTF-8 replaced by U+FFFD
+	EscapeRaw                     // nothing else escaped, bytes of non-ASCII characters copied as is without UTF-8 validation
+)
+
+// FloatFormat defines notation of Float values
+type FloatFormat byte
+
+const (
+	FloatShortest FloatFormat = iota // shortest round-trip representation, exponent for values < 1e-6 or >= 1e21, same as encoding/json
+	FloatFixed                       // decimal point without exponent, 1e300 written as 301 digits
+	FloatExponent                    // always with exponent: 1.5e+00
+)
+
+// NonFinitePolicy defines output of NaN, Infinity and -Infinity
+type NonFinitePolicy byte
+
+const (
+	NonFiniteError   NonFinitePolicy = iota // serialization fails with NonFiniteFloatError, same as encoding/json
+	NonFiniteNull                           // written as null
+	NonFiniteString                         // written as strings "NaN", "Infinity" and "-Infinity"
+	NonFiniteLiteral                        // written as JSON5 literals NaN, Infinity and -Infinity
+)
+
+// JStructSerializeFn writes compact JSON of v to wr, object keys sorted
+func JStructSerializeFn(v JStructOps, wr io.Writer) error {
+	return JStructSerializeWithOptions(v, wr, SerializeOptions{})
+}
+
+// JStructSerializeWithOptions writes compact JSON of v to wr formatted according to opts
+func JStructSerializeWithOptions(v JStructOps, wr io.Writer, opts SerializeOptions) error {
+	w := NewJStructWriterWithOptions(wr, opts)
+	e := w.Value(v)
+	if e != nil {
+		return e
+	}
+	return w.Close()
 }
 
 //func (s *JsonStruct) ToJson() string {
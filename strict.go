@@ -0,0 +1,76 @@
+package JsonStruct
+
+import (
+	"math"
+	"time"
+)
+
+// Strict conversions shared by StrictOps implementations,
+// values of other types fail with TypeMismatchError, numbers out of range with NumberOverflowError.
+
+func boolE(v JStructOps) (bool, error) {
+	switch v.Type() {
+	case False:
+		return false, nil
+	case True:
+		return true, nil
+	}
+	return false, TypeMismatchError{Expected: True, Actual: v.Type()}
+}
+
+// intE accepts Int and Uint up to math.MaxInt64
+func intE(v JStructOps) (int64, error) {
+	switch v.Type() {
+	case Int:
+		return v.Int(), nil
+	case Uint:
+		n := v.Uint()
+		if n > math.MaxInt64 {
+			return 0, NumberOverflowError
+		}
+		return int64(n), nil
+	}
+	return 0, TypeMismatchError{Expected: Int, Actual: v.Type()}
+}
+
+// uintE accepts Uint and non-negative Int
+func uintE(v JStructOps) (uint64, error) {
+	switch v.Type() {
+	case Uint:
+		return v.Uint(), nil
+	case Int:
+		n := v.Int()
+		if n < 0 {
+			return 0, NumberOverflowError
+		}
+		return uint64(n), nil
+	}
+	return 0, TypeMismatchError{Expected: Uint, Actual: v.Type()}
+}
+
+// floatE accepts any number, integers converted to the nearest float
+func floatE(v JStructOps) (float64, error) {
+	switch v.Type() {
+	case Float:
+		return v.Float(), nil
+	case Int:
+		return float64(v.Int()), nil
+	case Uint:
+		return float64(v.Uint()), nil
+	}
+	return 0, TypeMismatchError{Expected: Float, Actual: v.Type()}
+}
+
+func stringE(v JStructOps) (string, error) {
+	if v.Type() != String {
+		return "", TypeMismatchError{Expected: String, Actual: v.Type()}
+	}
+	return v.String(), nil
+}
+
+func timeE(v JStructOps) (time.Time, error) {
+	if v.Type() != Time {
+		return time.Time{}, TypeMismatchError{Expected: Time, Actual: v.Type()}
+	}
+	return v.Time(), nil
+}
@@ -0,0 +1,409 @@
+package JsonStruct
+
+import (
+	"bytes"
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Tape entry tags, tag stored in the high byte of entry
+const (
+	tapeRoot      = 'r' // payload is index of the closing root entry
+	tapeObject    = '{' // payload is index of entry after the end of object
+	tapeObjectEnd = '}' // payload is offset of table in Tape items: number of members and entries of their keys
+	tapeArray     = '['
+	tapeArrayEnd  = ']' // table of items same as tapeObjectEnd
+	tapeString    = '"' // payload is offset in Tape strings, the next entry is length
+	tapeTime      = 'T' // string in RFC3339 format
+	tapeInt       = 'l' // the next entry is value
+	tapeUint      = 'u'
+	tapeFloat     = 'd' // the next entry is bits of value
+	tapeNull      = 'n'
+	tapeTrue      = 't'
+	tapeFalse     = 'f'
+)
+
+const (
+	tapeTagShift = 56
+	tapePayload  = 1<<tapeTagShift - 1
+	// tapeMaxSize is max length of source, structural indexes and entries of items are 32-bit
+	tapeMaxSize = math.MaxUint32 - 4
+)
+
+// Tape is flat representation of JSON built by JStructParseTape.
+// Values stored as sequence of tagged 64-bit entries, strings are unescaped to separate buffer.
+// Tables of items give access to members and items of containers by index without walking through entries.
+type Tape struct {
+	entries  []uint64
+	strs     []byte
+	items    []uint32
+	index    []uint32 // structural indexes of source
+	stack    []tapeContainer
+	children []uint32 // items of open containers
+}
+
+// JStructParseTape parses b into tape reusing memory of reuse if not nil,
+// values of reused tape are not valid after parsing
+func JStructParseTape(b []byte, reuse *Tape) (*Tape, error) {
+	return JStructParseTapeWithOptions(b, reuse, ParseOptions{})
+}
+
+// JStructParseTapeWithOptions works as JStructParseTape, tape supports opts.NoTimeDetection only,
+// other options fail parsing with UnsupportedOptionError. Source over 4GB fails with TapeSizeExceededError.
+func JStructParseTapeWithOptions(b []byte, reuse *Tape, opts ParseOptions) (*Tape, error) {
+	if uint64(len(b)) > tapeMaxSize {
+		return nil, TapeSizeExceededError
+	}
+	if opts.Relaxed || opts.AliasInput || opts.limited() || opts.DuplicateKeys != DuplicateKeysLastWins ||
+		opts.Spans != nil || len(opts.TimeLayouts) > 0 || len(opts.TimeEpochKeys) > 0 || len(opts.BinaryPaths) > 0 {
+		return nil, UnsupportedOptionError
+	}
+	t := reuse
+	if t == nil {
+		t = &Tape{}
+	}
+	var x tapeIndexer
+	t.index = x.index(t.index[:0], b)
+	// every structural character adds up to 2 entries
+	if n := 2*len(t.index) + 2; cap(t.entries) < n {
+		t.entries = make([]uint64, 0, n)
+	}
+	t.entries = t.entries[:0]
+	t.strs = t.strs[:0]
+	t.items = t.items[:0]
+	p := tapeBuilder{t: t, src: b, stack: t.stack[:0], children: t.children[:0], noTime: opts.NoTimeDetection}
+	e := p.build()
+	t.stack, t.children = p.stack, p.children
+	if e != nil {
+		return nil, e
+	}
+	return t, nil
+}
+
+// Root returns view of root value
+func (t *Tape) Root() *TapeValue {
+	return &TapeValue{t: t, i: 1}
+}
+
+func (t *Tape) tag(i int) byte {
+	return byte(t.entries[i] >> tapeTagShift)
+}
+
+func (t *Tape) payload(i int) uint64 {
+	return t.entries[i] & tapePayload
+}
+
+// next returns index of entry after value started at i
+func (t *Tape) next(i int) int {
+	switch t.tag(i) {
+	case tapeObject, tapeArray:
+		return int(uint32(t.payload(i)))
+	case tapeString, tapeTime, tapeInt, tapeUint, tapeFloat:
+		return i + 2
+	}
+	return i + 1
+}
+
+// str returns content of string entry i
+func (t *Tape) str(i int) []byte {
+	off := t.payload(i)
+	return t.strs[off : off+t.entries[i+1]]
+}
+
+// tapeBuilder is stage 2 of tape parser, it walks structural indexes and appends entries of values
+type tapeBuilder struct {
+	t        *Tape
+	src      []byte
+	stack    []tapeContainer
+	children []uint32 // entries of items (keys for objects) of open containers
+	noTime   bool     // ParseOptions.NoTimeDetection
+}
+
+type tapeContainer struct {
+	at    int // opening entry
+	first int // the first item in children
+}
+
+// builder states
+const (
+	tapeStValue = iota
+	tapeStArrayFirst
+	tapeStObjectFirst
+	tapeStKey
+	tapeStNext // value completed, delimiter expected
+)
+
+func (p *tapeBuilder) build() error {
+	t := p.t
+	idx := t.index
+	n := len(idx)
+	if n == 0 {
+		return InvalidJsonError{Err: io.EOF}
+	}
+	t.entries = append(t.entries, tapeRoot<<tapeTagShift)
+	state := tapeStValue
+	for i := 0; i < n; i++ {
+		pos := int(idx[i])
+		ch := p.src[pos]
+		switch state {
+		case tapeStObjectFirst:
+			if ch == h.CloseBraceCh {
+				p.close(tapeObjectEnd)
+				state = tapeStNext
+				continue
+			}
+			fallthrough
+		case tapeStKey:
+			if ch != h.QuoteCh {
+				return InvalidJsonTokenPtrError{Pos: pos}
+			}
+			p.children = append(p.children, uint32(len(t.entries)))
+			e := p.str(pos, false)
+			if e != nil {
+				return e
+			}
+			i++
+			if i == n {
+				return p.eof()
+			}
+			if p.src[idx[i]] != h.ColonCh {
+				return InvalidJsonTokenPtrError{Pos: int(idx[i])}
+			}
+			state = tapeStValue
+		case tapeStArrayFirst:
+			if ch == h.CloseBracketCh {
+				p.close(tapeArrayEnd)
+				state = tapeStNext
+				continue
+			}
+			fallthrough
+		case tapeStValue:
+			if l := len(p.stack); l > 0 && t.tag(p.stack[l-1].at) == tapeArray {
+				p.children = append(p.children, uint32(len(t.entries)))
+			}
+			switch ch {
+			case h.OpenBraceCh:
+				p.open(tapeObject)
+				state = tapeStObjectFirst
+			case h.OpenBracketCh:
+				p.open(tapeArray)
+				state = tapeStArrayFirst
+			default:
+				e := p.scalar(pos)
+				if e != nil {
+					return e
+				}
+				state = tapeStNext
+			}
+		case tapeStNext:
+			l := len(p.stack)
+			if l == 0 {
+				// data after root value
+				return InvalidJsonTokenPtrError{Pos: pos}
+			}
+			object := t.tag(p.stack[l-1].at) == tapeObject
+			switch {
+			case ch == h.CommaCh && object:
+				state = tapeStKey
+			case ch == h.CommaCh:
+				state = tapeStValue
+			case ch == h.CloseBraceCh && object:
+				p.close(tapeObjectEnd)
+			case ch == h.CloseBracketCh && !object:
+				p.close(tapeArrayEnd)
+			default:
+				return InvalidJsonTokenPtrError{Pos: pos}
+			}
+		}
+	}
+	if state != tapeStNext || len(p.stack) > 0 {
+		return p.eof()
+	}
+	t.entries[0] |= uint64(len(t.entries))
+	t.entries = append(t.entries, tapeRoot<<tapeTagShift)
+	return nil
+}
+
+func (p *tapeBuilder) eof() error {
+	return InvalidJsonPtrError{Err: io.EOF, Pos: len(p.src) - 1}
+}
+
+func (p *tapeBuilder) open(tag uint64) {
+	p.stack = append(p.stack, tapeContainer{at: len(p.t.entries), first: len(p.children)})
+	p.t.entries = append(p.t.entries, tag<<tapeTagShift)
+}
+
+// close moves items of container to its table
+func (p *tapeBuilder) close(tag uint64) {
+	l := len(p.stack) - 1
+	c := p.stack[l]
+	p.stack = p.stack[:l]
+	t := p.t
+	off := len(t.items)
+	t.items = append(t.items, uint32(len(p.children)-c.first))
+	t.items = append(t.items, p.children[c.first:]...)
+	p.children = p.children[:c.first]
+	t.entries = append(t.entries, tag<<tapeTagShift|uint64(off))
+	t.entries[c.at] |= uint64(len(t.entries))
+}
+
+func (p *tapeBuilder) scalar(pos int) error {
+	switch ch := p.src[pos]; {
+	case ch == h.QuoteCh:
+		return p.str(pos, true)
+	case ch == 'n':
+		return p.literal(pos, h.NullTokenData, tapeNull)
+	case ch == 't':
+		return p.literal(pos, h.TrueTokenData, tapeTrue)
+	case ch == 'f':
+		return p.literal(pos, h.FalseTokenData, tapeFalse)
+	case ch == h.MinusCh || h.NumCh[ch]:
+		return p.number(pos)
+	}
+	return InvalidJsonPtrError{Err: InvalidCharacterError, Pos: pos}
+}
+
+// delimited reports value ended at i followed by end of source, whitespace or delimiter
+func (p *tapeBuilder) delimited(i int) bool {
+	if i == len(p.src) {
+		return true
+	}
+	ch := p.src[i]
+	return h.SpaceCh[ch] || ch == h.CommaCh || ch == h.CloseBracketCh || ch == h.CloseBraceCh
+}
+
+func (p *tapeBuilder) literal(pos int, lit []byte, tag uint64) error {
+	end := pos + len(lit)
+	if end > len(p.src) || !bytes.Equal(p.src[pos:end], lit) || !p.delimited(end) {
+		return InvalidJsonPtrError{Err: InvalidCharacterError, Pos: pos}
+	}
+	p.t.entries = append(p.t.entries, tag<<tapeTagShift)
+	return nil
+}
+
+// number validates number started at pos and appends its value resolved same as parseNumber
+func (p *tapeBuilder) number(pos int) error {
+	src := p.src
+	l := len(src)
+	i := pos
+	if src[i] == h.MinusCh {
+		i++
+	}
+	if i == l || !h.NumCh[src[i]] {
+		return InvalidJsonPtrError{Err: InvalidCharacterError, Pos: i}
+	}
+	if src[i] == '0' {
+		i++
+	} else {
+		i = skipDigits(src, i)
+	}
+	float := false
+	if i < l && src[i] == h.PointCh {
+		float = true
+		i++
+		if i == l || !h.NumCh[src[i]] {
+			return InvalidJsonPtrError{Err: InvalidCharacterError, Pos: i}
+		}
+		i = skipDigits(src, i)
+	}
+	if i < l && (src[i] == h.ExpSmCh || src[i] == h.ExpCh) {
+		float = true
+		i++
+		if i < l && (src[i] == h.PlusCh || src[i] == h.MinusCh) {
+			i++
+		}
+		if i == l || !h.NumCh[src[i]] {
+			return InvalidJsonPtrError{Err: InvalidCharacterError, Pos: i}
+		}
+		i = skipDigits(src, i)
+	}
+	if !p.delimited(i) {
+		return InvalidJsonPtrError{Err: InvalidCharacterError, Pos: i}
+	}
+	str := bytesToString(src[pos:i])
+	t := p.t
+	if !float {
+		if n, ok := h.StringToInt(str); ok {
+			t.entries = append(t.entries, tapeInt<<tapeTagShift, uint64(n))
+			return nil
+		}
+		if n, ok := h.StringToUint(str); ok && src[pos] != h.MinusCh {
+			t.entries = append(t.entries, tapeUint<<tapeTagShift, n)
+			return nil
+		}
+	}
+	// out of range values resolved as +/-Inf
+	f, _ := strconv.ParseFloat(str, 64)
+	t.entries = append(t.entries, tapeFloat<<tapeTagShift, math.Float64bits(f))
+	return nil
+}
+
+func skipDigits(b []byte, i int) int {
+	for i < len(b) && h.NumCh[b[i]] {
+		i++
+	}
+	return i
+}
+
+// str validates string started at pos and appends it unescaped to strings of tape,
+// value strings in RFC3339 format resolved as time unless noTime set
+func (p *tapeBuilder) str(pos int, value bool) error {
+	src := p.src
+	l := len(src)
+	escaped := false
+	i := pos + 1
+	for {
+		if i >= l {
+			return p.eof()
+		}
+		ch := src[i]
+		if ch == h.QuoteCh {
+			break
+		}
+		if ch < 0x20 {
+			return InvalidJsonPtrError{Err: InvalidCharacterError, Pos: i}
+		}
+		if ch == h.BackSlashCh {
+			escaped = true
+			i++
+			if i == l {
+				return p.eof()
+			}
+			switch src[i] {
+			case 'u':
+				for j := 0; j < 4; j++ {
+					i++
+					if i == l {
+						return p.eof()
+					}
+					if !h.HexCh[src[i]] {
+						return InvalidJsonPtrError{Err: InvalidHexNumberError, Pos: i}
+					}
+				}
+			case h.QuoteCh, '/', h.BackSlashCh, 'b', 'f', 'n', 'r', 't':
+			default:
+				return InvalidJsonPtrError{Err: InvalidEscapeCharacterError, Pos: i}
+			}
+		}
+		i++
+	}
+	t := p.t
+	off := len(t.strs)
+	raw := src[pos+1 : i]
+	if escaped {
+		t.strs = appendUnescaped(t.strs, raw)
+	} else {
+		t.strs = append(t.strs, raw...)
+	}
+	var tag uint64 = tapeString
+	if value && !p.noTime && h.IsTimeFormat(src[pos:i+1]) {
+		if _, e := time.Parse(time.RFC3339, bytesToString(raw)); e == nil {
+			tag = tapeTime
+		}
+	}
+	t.entries = append(t.entries, tag<<tapeTagShift|uint64(off), uint64(len(t.strs)-off))
+	return nil
+}
@@ -0,0 +1,110 @@
+package JsonStruct
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	swarLsb   = 0x0101010101010101
+	swarLow7  = 0x7f7f7f7f7f7f7f7f
+	swarCase  = 0x2020202020202020
+	swarGlue  = 0x0102040810204080
+	tapeOddBs = 0xaaaaaaaaaaaaaaaa
+)
+
+// tapeIndexer is stage 1 of tape parser, it finds structural characters of source in blocks of 64 bytes.
+// Every block classified word at a time into bitmaps, one bit per byte.
+// Structural index consists of brackets, commas and colons outside of strings,
+// opening quotes and the first bytes of literals and numbers.
+type tapeIndexer struct {
+	escaped  uint64 // the first byte of the next block is escaped
+	inString uint64 // all ones if the next block starts inside of string
+	pseudo   uint64 // the last byte of previous block precedes value
+}
+
+// index appends structural indexes of src to dst
+func (x *tapeIndexer) index(dst []uint32, src []byte) []uint32 {
+	// start of source precedes value same as whitespace
+	*x = tapeIndexer{pseudo: 1}
+	var pad [64]byte
+	l := len(src)
+	for off := 0; off < l; off += 64 {
+		block := src[off:]
+		if len(block) < 64 {
+			// the tail padded by spaces
+			for i := copy(pad[:], block); i < 64; i++ {
+				pad[i] = ' '
+			}
+			block = pad[:]
+		}
+		st := x.structurals(block)
+		for st != 0 {
+			dst = append(dst, uint32(off+bits.TrailingZeros64(st)))
+			st &= st - 1
+		}
+	}
+	return dst
+}
+
+// structurals returns bitmap of structural characters in block of 64 bytes
+func (x *tapeIndexer) structurals(block []byte) uint64 {
+	var quote, bs, op, ws uint64
+	for i := 0; i < 8; i++ {
+		w := binary.LittleEndian.Uint64(block[i*8:])
+		shift := uint(i * 8)
+		quote |= swarEq(w, '"') << shift
+		bs |= swarEq(w, '\\') << shift
+		// '[' and ']' differ from '{' and '}' by case bit only
+		op |= (swarEq(w|swarCase, '{') | swarEq(w|swarCase, '}') | swarEq(w, ':') | swarEq(w, ',')) << shift
+		ws |= (swarEq(w, ' ') | swarEq(w, '\t') | swarEq(w, '\n') | swarEq(w, '\r')) << shift
+	}
+	quote &^= x.escapedBits(bs)
+	inString := prefixXor(quote) ^ x.inString
+	x.inString = uint64(int64(inString) >> 63)
+
+	st := op&^inString | quote
+	// the first byte of literal or number follows structural character or whitespace
+	pred := st | ws
+	follows := pred<<1 | x.pseudo
+	x.pseudo = pred >> 63
+	st |= follows &^ ws &^ inString
+	// closing quotes are not structural
+	return st &^ (quote &^ inString)
+}
+
+// escapedBits returns bitmap of bytes escaped by backslashes bs
+func (x *tapeIndexer) escapedBits(bs uint64) uint64 {
+	if bs == 0 {
+		escaped := x.escaped
+		x.escaped = 0
+		return escaped
+	}
+	// backslash escaped by previous block is not escape
+	potential := bs &^ x.escaped
+	// subtraction marks odd positions of backslash series started at even positions and vice versa
+	codes := (potential<<1 | tapeOddBs) - potential ^ tapeOddBs
+	escaped := codes ^ (bs | x.escaped)
+	x.escaped = (codes & bs) >> 63
+	return escaped
+}
+
+// swarEq returns 8 bit mask of bytes of word w equal to ch
+func swarEq(w uint64, ch byte) uint64 {
+	v := w ^ swarLsb*uint64(ch)
+	// high bit set for zero bytes only, no borrows between bytes
+	z := ^((v&swarLow7 + swarLow7) | v | swarLow7)
+	// gather high bits to the top byte
+	return (z >> 7) * swarGlue >> 56
+}
+
+// prefixXor returns bitmap with bits set from every odd set bit of v till the next set bit
+func prefixXor(v uint64) uint64 {
+	v ^= v << 1
+	v ^= v << 2
+	v ^= v << 4
+	v ^= v << 8
+	v ^= v << 16
+	v ^= v << 32
+	return v
+}
@@ -0,0 +1,475 @@
+package JsonStruct
+
+import (
+	"bytes"
+	"iter"
+	"math"
+	"time"
+)
+
+// TapeValue is read-only JStructOps view of value in Tape.
+// Setters panic with ReadOnlyError, operations returning error report it instead.
+type TapeValue struct {
+	t *Tape
+	i int // the first entry of value
+}
+
+// primitive resolves value as JsonStruct to share conversions with it, containers keep type only
+func (v *TapeValue) primitive() *JsonStruct {
+	js := &JsonStruct{}
+	t := v.t
+	switch t.tag(v.i) {
+	case tapeTrue:
+		js.SetBool(true)
+	case tapeFalse:
+		js.SetBool(false)
+	case tapeInt:
+		js.SetInt(int64(t.entries[v.i+1]))
+	case tapeUint:
+		js.SetUint(t.entries[v.i+1])
+	case tapeFloat:
+		js.SetFloat(math.Float64frombits(t.entries[v.i+1]))
+	case tapeString:
+		js.SetString(string(t.str(v.i)))
+	case tapeTime:
+		tm, _ := time.Parse(time.RFC3339, bytesToString(t.str(v.i)))
+		js.SetParsedTime(tm, v.TimeSource())
+	case tapeObject:
+		js.valType = Object
+	case tapeArray:
+		js.valType = Array
+	}
+	return js
+}
+
+// TimeSource returns JSON token of Time value, time strings have no escape sequences
+func (v *TapeValue) TimeSource() string {
+	if v.t.tag(v.i) != tapeTime {
+		return ""
+	}
+	return `"` + string(v.t.str(v.i)) + `"`
+}
+
+// items returns table of container entries, keys of members for objects
+func (v *TapeValue) items() []uint32 {
+	t := v.t
+	off := t.payload(t.next(v.i) - 1)
+	n := uint64(t.items[off])
+	return t.items[off+1 : off+1+n]
+}
+
+// tapeScanMembers is max number of members checked for repeated keys without map
+const tapeScanMembers = 16
+
+// members returns key entries of object members, repeated keys resolved to the last one same as GetKey,
+// so member of repeated key placed by its last occurrence
+func (v *TapeValue) members() []uint32 {
+	t := v.t
+	items := v.items()
+	var last map[string]int
+	if len(items) > tapeScanMembers {
+		last = make(map[string]int, len(items))
+		for j, k := range items {
+			last[bytesToString(t.str(int(k)))] = j
+		}
+	}
+	var res []uint32 // nil while no repeated keys found
+	for j, k := range items {
+		key := t.str(int(k))
+		repeated := false
+		if last != nil {
+			repeated = last[bytesToString(key)] != j
+		} else {
+			for _, nk := range items[j+1:] {
+				if bytes.Equal(t.str(int(nk)), key) {
+					repeated = true
+					break
+				}
+			}
+		}
+		switch {
+		case repeated && res == nil:
+			res = append(make([]uint32, 0, len(items)-1), items[:j]...)
+		case !repeated && res != nil:
+			res = append(res, k)
+		}
+	}
+	if res == nil {
+		return items
+	}
+	return res
+}
+
+// each calls fn for members of object or items of array till fn returns false, k is entry of key
+func (v *TapeValue) each(fn func(k, i int) bool) {
+	t := v.t
+	tag := t.tag(v.i)
+	if tag != tapeObject && tag != tapeArray {
+		return
+	}
+	end := t.next(v.i) - 1
+	for i := v.i + 1; i < end; i = t.next(i) {
+		k := -1
+		if tag == tapeObject {
+			k = i
+			i += 2
+		}
+		if !fn(k, i) {
+			return
+		}
+	}
+}
+
+//region Json Unmarshal / Marshal
+
+// UnmarshalJSON parses b into new tape
+func (v *TapeValue) UnmarshalJSON(b []byte) error {
+	t, e := JStructParseTape(b, nil)
+	if e != nil {
+		return e
+	}
+	*v = *t.Root()
+	return nil
+}
+
+func (v *TapeValue) MarshalJSON() ([]byte, error) {
+	return appendJStruct(nil, v, SerializeOptions{})
+}
+
+//endregion
+
+//region General operations
+
+func (v *TapeValue) Type() Type {
+	switch v.t.tag(v.i) {
+	case tapeTrue:
+		return True
+	case tapeFalse:
+		return False
+	case tapeInt:
+		return Int
+	case tapeUint:
+		return Uint
+	case tapeFloat:
+		return Float
+	case tapeString:
+		return String
+	case tapeTime:
+		return Time
+	case tapeObject:
+		return Object
+	case tapeArray:
+		return Array
+	}
+	return Null
+}
+
+func (v *TapeValue) Value() interface{} {
+	switch v.t.tag(v.i) {
+	case tapeObject:
+		m := make(map[string]JStructOps, v.Size())
+		v.each(func(k, i int) bool {
+			m[string(v.t.str(k))] = &TapeValue{t: v.t, i: i}
+			return true
+		})
+		return m
+	case tapeArray:
+		l := make([]JStructOps, 0, v.Size())
+		v.each(func(_, i int) bool {
+			l = append(l, &TapeValue{t: v.t, i: i})
+			return true
+		})
+		return l
+	}
+	return v.primitive().Value()
+}
+
+func (v *TapeValue) Size() int {
+	t := v.t
+	switch t.tag(v.i) {
+	case tapeString, tapeTime:
+		return int(t.entries[v.i+1])
+	case tapeObject:
+		return len(v.members())
+	case tapeArray:
+		return len(v.items())
+	}
+	return -1
+}
+
+func (v *TapeValue) IsNull() bool {
+	return v.t.tag(v.i) == tapeNull
+}
+
+func (v *TapeValue) SetNull() {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) IsObject() bool {
+	return v.t.tag(v.i) == tapeObject
+}
+
+func (v *TapeValue) AsObject() {
+	if !v.IsObject() {
+		panic(ReadOnlyError)
+	}
+}
+
+func (v *TapeValue) IsArray() bool {
+	return v.t.tag(v.i) == tapeArray
+}
+
+func (v *TapeValue) AsArray() {
+	if !v.IsArray() {
+		panic(ReadOnlyError)
+	}
+}
+
+//endregion General operations
+
+//region Primitive operations
+
+func (v *TapeValue) IsBool() bool {
+	tag := v.t.tag(v.i)
+	return tag == tapeTrue || tag == tapeFalse
+}
+
+func (v *TapeValue) SetBool(bool) {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) Bool() bool {
+	return v.primitive().Bool()
+}
+
+func (v *TapeValue) IsNumber() bool {
+	tag := v.t.tag(v.i)
+	return tag == tapeInt || tag == tapeUint || tag == tapeFloat
+}
+
+func (v *TapeValue) IsInt() bool {
+	return v.t.tag(v.i) == tapeInt
+}
+
+func (v *TapeValue) SetInt(int64) {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) Int() int64 {
+	return v.primitive().Int()
+}
+
+func (v *TapeValue) IsUint() bool {
+	return v.t.tag(v.i) == tapeUint
+}
+
+func (v *TapeValue) SetUint(uint64) {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) Uint() uint64 {
+	return v.primitive().Uint()
+}
+
+func (v *TapeValue) IsFloat() bool {
+	return v.t.tag(v.i) == tapeFloat
+}
+
+func (v *TapeValue) SetFloat(float64) {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) Float() float64 {
+	return v.primitive().Float()
+}
+
+func (v *TapeValue) IsString() bool {
+	return v.t.tag(v.i) == tapeString
+}
+
+func (v *TapeValue) SetString(string) {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) String() string {
+	return v.primitive().String()
+}
+
+func (v *TapeValue) IsTime() bool {
+	return v.t.tag(v.i) == tapeTime
+}
+
+func (v *TapeValue) SetTime(time.Time) {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) Time() time.Time {
+	return v.primitive().Time()
+}
+
+func (v *TapeValue) SetParsedTime(time.Time, string) {
+	panic(ReadOnlyError)
+}
+
+// IsBinary is always false, tape keeps base64 data as strings
+func (v *TapeValue) IsBinary() bool {
+	return false
+}
+
+func (v *TapeValue) SetBytes([]byte) {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) Bytes() []byte {
+	return v.primitive().Bytes()
+}
+
+//endregion Primitive operations
+
+//region Strict operations
+
+func (v *TapeValue) BoolE() (bool, error) {
+	return boolE(v)
+}
+
+func (v *TapeValue) IntE() (int64, error) {
+	return intE(v)
+}
+
+func (v *TapeValue) UintE() (uint64, error) {
+	return uintE(v)
+}
+
+func (v *TapeValue) FloatE() (float64, error) {
+	return floatE(v)
+}
+
+func (v *TapeValue) StringE() (string, error) {
+	return stringE(v)
+}
+
+func (v *TapeValue) TimeE() (time.Time, error) {
+	return timeE(v)
+}
+
+//endregion Strict operations
+
+//region Object operations
+
+func (v *TapeValue) SetKey(string, interface{}) error {
+	return ReadOnlyError
+}
+
+// GetKey returns value of key, the last one for repeated keys
+func (v *TapeValue) GetKey(key string) JStructOps {
+	k := v.lastKey(key)
+	if k < 0 {
+		return nil
+	}
+	return &TapeValue{t: v.t, i: k + 2}
+}
+
+// lastKey returns entry of the last member key of object, -1 if not found
+func (v *TapeValue) lastKey(key string) int {
+	if !v.IsObject() {
+		return -1
+	}
+	members := v.items()
+	for j := len(members) - 1; j >= 0; j-- {
+		k := int(members[j])
+		if string(v.t.str(k)) == key {
+			return k
+		}
+	}
+	return -1
+}
+
+func (v *TapeValue) RemoveKey(string) JStructOps {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) HasKey(key string) bool {
+	return v.lastKey(key) >= 0
+}
+
+// Keys returns keys in order of source, repeated keys listed once by the last occurrence
+func (v *TapeValue) Keys() []string {
+	if !v.IsObject() {
+		return []string{}
+	}
+	members := v.members()
+	keys := make([]string, len(members))
+	for j, k := range members {
+		keys[j] = string(v.t.str(int(k)))
+	}
+	return keys
+}
+
+//endregion Object operations
+
+//region Array operations
+
+func (v *TapeValue) Push(interface{}) error {
+	return ReadOnlyError
+}
+
+func (v *TapeValue) Pop() JStructOps {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) Shift() JStructOps {
+	panic(ReadOnlyError)
+}
+
+func (v *TapeValue) SetIndex(int, interface{}) error {
+	return ReadOnlyError
+}
+
+func (v *TapeValue) GetIndex(idx int) JStructOps {
+	if !v.IsArray() {
+		return nil
+	}
+	items := v.items()
+	if idx < 0 || idx >= len(items) {
+		return nil
+	}
+	return &TapeValue{t: v.t, i: int(items[idx])}
+}
+
+//endregion Array operations
+
+//region Iteration operations
+
+// ForEachKey calls fn for members in order of Keys, repeated keys resolved to the last value same as GetKey
+func (v *TapeValue) ForEachKey(fn func(key string, v JStructOps) bool) {
+	if !v.IsObject() {
+		return
+	}
+	for _, k := range v.members() {
+		if !fn(string(v.t.str(int(k))), &TapeValue{t: v.t, i: int(k) + 2}) {
+			return
+		}
+	}
+}
+
+func (v *TapeValue) ForEachIndex(fn func(idx int, v JStructOps) bool) {
+	if !v.IsArray() {
+		return
+	}
+	idx := 0
+	v.each(func(_, i int) bool {
+		ok := fn(idx, &TapeValue{t: v.t, i: i})
+		idx++
+		return ok
+	})
+}
+
+func (v *TapeValue) Entries() iter.Seq2[string, JStructOps] {
+	return v.ForEachKey
+}
+
+func (v *TapeValue) All() iter.Seq2[int, JStructOps] {
+	return v.ForEachIndex
+}
+
+//endregion Iteration operations
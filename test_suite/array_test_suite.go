@@ -157,3 +157,63 @@ func (s *ObjectOpsTestSuite) TestSetIndexUnsupportedType() {
 	s.ErrorIs(err, djs.UnsupportedTypeError)
 	s.Equal(0, s.js.Size())
 }
+
+func (s *ArrayOpsTestSuite) TestForEachIndex() {
+	it, ok := s.js.(djs.IterOps)
+	s.Require().True(ok)
+	calls := 0
+	it.ForEachIndex(func(int, djs.JStructOps) bool {
+		calls++
+		return true
+	})
+	s.Equal(0, calls)
+	s.js.AsArray()
+	exp := []string{"a", "b", "c"}
+	for _, v := range exp {
+		s.js.Push(v)
+	}
+	var res []string
+	it.ForEachIndex(func(i int, v djs.JStructOps) bool {
+		s.Equal(len(res), i)
+		res = append(res, v.String())
+		return true
+	})
+	s.Equal(exp, res)
+	res = nil
+	for i, v := range it.All() {
+		s.Equal(len(res), i)
+		res = append(res, v.String())
+	}
+	s.Equal(exp, res)
+	// early exit
+	res = nil
+	it.ForEachIndex(func(_ int, v djs.JStructOps) bool {
+		res = append(res, v.String())
+		return len(res) < 2
+	})
+	s.Equal([]string{"a", "b"}, res)
+	res = nil
+	for _, v := range it.All() {
+		if v.String() == "b" {
+			break
+		}
+		res = append(res, v.String())
+	}
+	s.Equal([]string{"a"}, res)
+	// holes left by SetIndex past the end passed as nil interface same as GetIndex
+	s.NoError(s.js.SetIndex(5, "f"))
+	res = nil
+	it.ForEachIndex(func(i int, v djs.JStructOps) bool {
+		if v == nil {
+			res = append(res, "")
+			return true
+		}
+		res = append(res, v.String())
+		return true
+	})
+	s.Equal([]string{"a", "b", "c", "", "", "f"}, res)
+	// arrays have no members
+	for range it.Entries() {
+		s.Fail("array iterated as object")
+	}
+}
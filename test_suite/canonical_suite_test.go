@@ -0,0 +1,145 @@
+package test_suite
+
+import (
+	"bytes"
+	djs "github.com/Pencroff/JsonStruct"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestJStruct_Canonical(t *testing.T) {
+	s := new(CanonicalTestSuite)
+	suite.Run(t, s)
+}
+
+type CanonicalTestSuite struct {
+	suite.Suite
+}
+
+// RFC 8785 examples and test data of reference implementation
+func (s *CanonicalTestSuite) TestCanonical_Vectors() {
+	for _, el := range []struct {
+		in  string
+		out string
+	}{
+		{`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`, `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`},
+		{`[
+  56,
+  {
+    "d": true,
+    "10": null,
+    "1": [ ]
+  }
+]`, `[56,{"1":[],"10":null,"d":true}]`},
+		{`{
+  "1": {"f": {"f": "hi","F": 5} ,"\n": 56.0},
+  "10": { },
+  "": "empty",
+  "a": { },
+  "111": [ {"e": "yes","E": "no" } ],
+  "A": { }
+}`, `{"":"empty","1":{"\n":56,"f":{"F":5,"f":"hi"}},"10":{},"111":[{"E":"no","e":"yes"}],"A":{},"a":{}}`},
+		{`{
+  "Unnormalized Unicode":"A\u030a"
+}`, "{\"Unnormalized Unicode\":\"A\u030a\"}"},
+		{`{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\u000a": "Newline",
+  "1": "One",
+  "\u0080": "Control\u007f",
+  "\ud83d\ude02": "Smiley",
+  "\u00f6": "Latin Small Letter O With Diaeresis",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "</script>": "Browser Challenge"
+}`, "{\"\\n\":\"Newline\",\"\\r\":\"Carriage Return\",\"1\":\"One\",\"</script>\":\"Browser Challenge\"," +
+			"\"\u0080\":\"Control\u007f\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\"," +
+			"\"😂\":\"Smiley\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"},
+		{`["\u2028\u2029", "\u001f"]`, "[\"\u2028\u2029\",\"\\u001f\"]"},
+	} {
+		js := JsonStructFactory()
+		s.NoError(djs.JStructParseBytes([]byte(el.in), js))
+		b, e := djs.Canonicalize(js)
+		s.NoError(e)
+		s.Equal(el.out, string(b))
+		buf := bytes.Buffer{}
+		s.NoError(djs.JStructCanonicalize(js, &buf))
+		s.Equal(el.out, buf.String())
+	}
+}
+
+// RFC 8785 Appendix B
+func (s *CanonicalTestSuite) TestCanonical_Numbers() {
+	for _, el := range []struct {
+		bits uint64
+		out  string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	} {
+		js := JsonStructFactory()
+		js.SetFloat(math.Float64frombits(el.bits))
+		b, e := djs.Canonicalize(js)
+		s.NoError(e)
+		s.Equal(el.out, string(b), "%x", el.bits)
+	}
+	for _, bits := range []uint64{0x7fffffffffffffff, 0x7ff0000000000000, 0xfff0000000000000} {
+		js := JsonStructFactory()
+		js.SetFloat(math.Float64frombits(bits))
+		_, e := djs.Canonicalize(js)
+		s.ErrorIs(e, djs.NonCanonicalValueError, "%x", bits)
+	}
+}
+
+func (s *CanonicalTestSuite) TestCanonical_Values() {
+	js := JsonStructFactory()
+	js.AsObject()
+	s.NoError(js.SetKey("int", -9007199254740993+1))
+	s.NoError(js.SetKey("big", uint64(1)<<60))
+	s.NoError(js.SetKey("time", time.Date(2022, 1, 2, 3, 4, 5, 600, time.UTC)))
+	s.NoError(js.SetKey("nil", nil))
+	b, e := djs.Canonicalize(js)
+	s.NoError(e)
+	s.Equal(`{"big":1152921504606847000,"int":-9007199254740992,"nil":null,"time":"2022-01-02T03:04:05.0000006Z"}`, string(b))
+
+	for _, v := range []interface{}{int64(9007199254740993), uint64(math.MaxUint64), "a\xffb"} {
+		js = JsonStructFactory()
+		js.AsArray()
+		s.NoError(js.Push(v))
+		_, e = djs.Canonicalize(js)
+		s.ErrorIs(e, djs.NonCanonicalValueError, "%v", v)
+	}
+	js = JsonStructFactory()
+	js.AsObject()
+	s.NoError(js.SetKey("\xff", 1))
+	_, e = djs.Canonicalize(js)
+	s.ErrorIs(e, djs.NonCanonicalValueError)
+}
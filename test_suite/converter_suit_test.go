@@ -42,15 +42,14 @@ func (s *JsConverterTestSuite) TestUnmarshaler() {
 	djs.UnmarshalJSON = djs.UnmarshalJSONFn
 }
 
-func (s *JsConverterTestSuite) TestUnmarshalUseToParse() {
-	djs.JStructParse = s.mock.JStructParseFn
+func (s *JsConverterTestSuite) TestUnmarshalUseToParseBytes() {
+	djs.JStructParseBytes = s.mock.JStructParseBytesFn
 	data := []byte(`{"someKey":"value"}`)
 	js := s.factory()
-	rd := bytes.NewReader(data)
-	s.mock.On("JStructParseFn", rd, js).Return(nil)
+	s.mock.On("JStructParseBytesFn", data, js).Return(nil)
 	djs.UnmarshalJSON(data, js)
 	s.mock.AssertExpectations(s.T())
-	djs.JStructParse = djs.JStructParseFn
+	djs.JStructParseBytes = djs.JStructParseBytesFn
 }
 
 func (s *JsConverterTestSuite) TestMarshaler() {
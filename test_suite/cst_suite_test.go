@@ -0,0 +1,144 @@
+package test_suite
+
+import (
+	djs "github.com/Pencroff/JsonStruct"
+	tl "github.com/Pencroff/JsonStruct/tool"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestJStruct_Cst(t *testing.T) {
+	s := new(CstTestSuite)
+	suite.Run(t, s)
+}
+
+type CstTestSuite struct {
+	suite.Suite
+}
+
+const cstConfig = "// service config\n" + `{
+	"name": "api", // inline
+	/* block
+	   comment */
+	"port" :	8080,
+	'hosts': [ "a",
+		"b" , // last host
+	],
+	retries: 0x0A,
+	"empty": { /* nothing */ },
+	"nested": {"x": [ ], "y": -.5e+1}
+}
+// end
+`
+
+func (s *CstTestSuite) TestCst_RoundTrip() {
+	canada, e := tl.ReadGzip("../benchmark/data/canada.json.gz")
+	s.NoError(e)
+	relaxed := djs.ParseOptions{Relaxed: true}
+	for _, el := range []struct {
+		in   string
+		opts djs.ParseOptions
+	}{
+		{`null`, djs.ParseOptions{}},
+		{" \r\n\t-1.50E+2 \n", djs.ParseOptions{}},
+		{`"a\"bé𝄞"`, djs.ParseOptions{}},
+		{` [ 1 , [ ] , { } , [[ ]] ,{"a" :{"b":[null ,true]} } ] `, djs.ParseOptions{}},
+		{"{\r\n  \"a\": \"2015-05-14T12:34:56.379+02:00\",\r\n  \"b\": 18446744073709551616\r\n}\r\n", djs.ParseOptions{}},
+		{string(canada), djs.ParseOptions{}},
+		{cstConfig, relaxed},
+		{`/*a*/[/*b*/1/*c*/,/*d*/]/*e*/`, relaxed},
+		{"[ // ]\n 1 /* , */ , { // }\n } ]", relaxed},
+		{`{a:'x',b:+1,c:Infinity,d:NaN,e:5.,}`, relaxed},
+	} {
+		cst, e := djs.JStructParseCST([]byte(el.in), el.opts)
+		s.NoError(e, el.in)
+		s.Equal(el.in, string(cst.Source()))
+	}
+}
+
+func (s *CstTestSuite) TestCst_Values() {
+	cst, e := djs.JStructParseCST([]byte(cstConfig), djs.ParseOptions{Relaxed: true})
+	s.NoError(e)
+	s.Equal([]string{"name", "port", "hosts", "retries", "empty", "nested"}, cst.Keys())
+	s.Equal("api", cst.GetKey("name").String())
+	s.Equal(int64(8080), cst.GetKey("port").Int())
+	s.Equal(2, cst.GetKey("hosts").Size())
+	s.Equal("b", cst.GetKey("hosts").GetIndex(1).String())
+	s.Equal(int64(10), cst.GetKey("retries").Int())
+	s.Equal(0, cst.GetKey("empty").Size())
+	s.Equal(-5.0, cst.GetKey("nested").GetKey("y").Float())
+
+	name := cst.GetKey("name").(*djs.JStructCstNode)
+	s.Equal("\n\t", string(name.Leading))
+	s.Equal("", string(name.Trailing))
+	port := cst.GetKey("port").(*djs.JStructCstNode)
+	s.Equal(" // inline\n\t/* block\n\t   comment */\n\t", string(port.Leading))
+	last := cst.GetKey("hosts").GetIndex(1).(*djs.JStructCstNode)
+	s.Equal("\n\t\t", string(last.Leading))
+	s.Equal(" ", string(last.Trailing))
+	s.Equal("// service config\n", string(cst.Leading))
+	s.Equal("\n// end\n", string(cst.Trailing))
+}
+
+func (s *CstTestSuite) TestCst_Edit() {
+	in := `{
+	// listen port
+	"port": 8080, /* keep */
+	"tags": [ "a", "b" ],
+	"debug": false
+}
+`
+	cst, e := djs.JStructParseCST([]byte(in), djs.ParseOptions{Relaxed: true})
+	s.NoError(e)
+	cst.GetKey("port").SetInt(9090)
+	s.NoError(cst.GetKey("tags").Push("c\n"))
+	s.NoError(cst.SetKey("debug", true))
+	s.NoError(cst.SetKey("started", time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)))
+	s.Equal(`{
+	// listen port
+	"port": 9090, /* keep */
+	"tags": [ "a", "b", "c\n" ],
+	"debug": true,
+	"started": "2022-01-02T03:04:05Z"
+}
+`, string(cst.Source()))
+
+	cst.RemoveKey("started")
+	cst.RemoveKey("debug")
+	cst.GetKey("tags").Shift()
+	cst.GetKey("tags").Pop()
+	s.Equal(`{
+	// listen port
+	"port": 9090, /* keep */
+	"tags": [ "b" ]
+}
+`, string(cst.Source()))
+
+	// new object value replaces the old one in place of it
+	js := JsonStructFactory()
+	js.AsObject()
+	s.NoError(js.SetKey("b", 2))
+	s.NoError(js.SetKey("a", 1.5))
+	s.NoError(cst.SetKey("port", js))
+	cst.GetKey("tags").Pop()
+	s.Equal(`{
+	// listen port
+	"port": {"a":1.5,"b":2}, /* keep */
+	"tags": [ ]
+}
+`, string(cst.Source()))
+}
+
+func (s *CstTestSuite) TestCst_Errors() {
+	_, e := djs.JStructParseCST([]byte(`{"a":[{"b":1,"b":2}]}`), djs.ParseOptions{})
+	s.Equal(djs.DuplicateKeyError{Path: "/a/0/b", Pos: 13}, e)
+	_, e = djs.JStructParseCST([]byte(`[1,2`), djs.ParseOptions{})
+	s.Equal(djs.InvalidJsonPtrError{Err: io.EOF, Pos: 3}, e)
+	_, e = djs.JStructParseCST([]byte(`// c
+1`), djs.ParseOptions{})
+	s.Error(e)
+	_, e = djs.JStructParseCST([]byte(`[[[1]]]`), djs.ParseOptions{MaxDepth: 2})
+	s.ErrorIs(e, djs.MaxDepthExceededError)
+}
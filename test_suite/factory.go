@@ -20,3 +20,11 @@ func JsonStructValueFactory() djs.JStructOps {
 func JsonStructPointerFactory() djs.JStructOps {
 	return &experiment.JsonStructPtr{}
 }
+
+func JsonStructCstFactory() djs.JStructOps {
+	return djs.NewJStructCstNode()
+}
+
+func JsonStructLazyFactory() djs.JStructOps {
+	return &djs.JsonStructLazy{}
+}
@@ -0,0 +1,173 @@
+package test_suite
+
+import (
+	"bytes"
+	"encoding/json"
+	djs "github.com/Pencroff/JsonStruct"
+	tl "github.com/Pencroff/JsonStruct/tool"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestJStruct_Formatter(t *testing.T) {
+	s := new(FormatterTestSuite)
+	suite.Run(t, s)
+}
+
+type FormatterTestSuite struct {
+	suite.Suite
+}
+
+// arrayReader generates array of n items without keeping it in memory
+type arrayReader struct {
+	n    int
+	item string
+	line []byte // the current item with separator
+	off  int    // read bytes of line
+}
+
+func (r *arrayReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if r.off == len(r.line) {
+			if r.n == 0 {
+				break
+			}
+			r.n--
+			sep := ",\n  "
+			if r.n == 0 {
+				sep = "\n]"
+			}
+			r.line = append(append(r.line[:0], r.item...), sep...)
+			r.off = 0
+		}
+		c := copy(p[n:], r.line[r.off:])
+		r.off += c
+		n += c
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+func (s *FormatterTestSuite) TestFormatter_SameAsStd() {
+	inputs := []string{
+		`null`, ` -1.50E+2 `, `"aé\"\/b"`, `[]`, `{}`, `[[],{}]`, "true\r\n", "\t[1] \n",
+		"\"a\"  ", `{"a":1}` + "\n\n",
+		` [ 1 , [ ] , { } , [[ 0.0 ]] ,{"a" :{"b":[null ,true, "\n\t"]} } ] `,
+		"{\r\n  \"a\": \"2015-05-14T12:34:56.379+02:00\",\r\n  \"b\": 18446744073709551616, \"c\": [1e400]\r\n}",
+	}
+	for _, name := range []string{"canada", "twitter"} {
+		b, e := tl.ReadGzip("../benchmark/data/" + name + ".json.gz")
+		s.NoError(e)
+		inputs = append(inputs, string(b))
+	}
+	for _, in := range inputs {
+		exp := bytes.Buffer{}
+		s.NoError(json.Compact(&exp, []byte(in)))
+		act := bytes.Buffer{}
+		s.NoError(djs.Compact(&act, strings.NewReader(in)), in)
+		s.Equal(exp.String(), act.String())
+
+		exp.Reset()
+		s.NoError(json.Indent(&exp, []byte(in), "> ", "\t"))
+		act.Reset()
+		s.NoError(djs.Indent(&act, strings.NewReader(in), "> ", "\t"), in)
+		s.Equal(exp.String(), act.String())
+	}
+}
+
+func (s *FormatterTestSuite) TestFormatter_Stream() {
+	items := 100000
+	rd := io.MultiReader(strings.NewReader("[\n  "), &arrayReader{n: items, item: `{"id": 12345, "name": "item \"x\""}`})
+	act := bytes.Buffer{}
+	s.NoError(djs.Compact(&act, rd))
+	item := `{"id":12345,"name":"item \"x\""}`
+	s.Equal(items*(len(item)+1)+1, act.Len())
+	s.True(strings.HasPrefix(act.String(), "["+item+","+item))
+	s.True(strings.HasSuffix(act.String(), item+"]"))
+}
+
+func (s *FormatterTestSuite) TestFormatter_ConstantMemory() {
+	format := map[string]func(io.Writer, io.Reader) error{
+		"compact": djs.Compact,
+		"indent":  func(dst io.Writer, src io.Reader) error { return djs.Indent(dst, src, "", "  ") },
+	}
+	for name, fn := range format {
+		var allocs []uint64
+		for _, items := range []int{1 << 16, 1 << 18} {
+			rd := io.MultiReader(strings.NewReader("[\n  "), &arrayReader{n: items, item: `[1, "item", {"a": null}]`})
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			s.NoError(fn(io.Discard, rd))
+			runtime.ReadMemStats(&after)
+			allocs = append(allocs, after.TotalAlloc-before.TotalAlloc)
+		}
+		// 4 times larger input (about 8MB) formatted in memory of the same bound
+		s.Less(allocs[1], uint64(256*1024), name)
+		s.Less(allocs[1], 2*allocs[0]+64*1024, name)
+	}
+}
+
+func (s *FormatterTestSuite) TestFormatter_Errors() {
+	for _, el := range []struct {
+		in  string
+		err error
+	}{
+		{``, djs.InvalidJsonError{Err: io.EOF}},
+		{`[1,2`, djs.InvalidJsonPtrError{Err: io.EOF, Pos: 3}},
+		{`{} {}`, djs.InvalidJsonTokenPtrError{Pos: 3}},
+	} {
+		s.Equal(el.err, djs.Compact(io.Discard, strings.NewReader(el.in)), el.in)
+		s.Equal(el.err, djs.Indent(io.Discard, strings.NewReader(el.in), "", "  "), el.in)
+	}
+}
+
+// failWriter fails after n bytes written, after counts calls following the failure
+type failWriter struct {
+	n      int
+	failed bool
+	after  int
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if w.failed {
+		w.after++
+	}
+	if len(p) > w.n {
+		w.failed = true
+		n := w.n
+		w.n = 0
+		return n, io.ErrClosedPipe
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func (s *FormatterTestSuite) TestFormatter_WriteError() {
+	item := `{"id": 12345, "name": "item \"x\""}`
+	for _, format := range []func(io.Writer, io.Reader) error{
+		djs.Compact,
+		func(dst io.Writer, src io.Reader) error { return djs.Indent(dst, src, "", "  ") },
+	} {
+		src := &arrayReader{n: 100000, item: item}
+		w := &failWriter{n: 10000}
+		e := format(w, io.MultiReader(strings.NewReader("[\n  "), src))
+		s.Equal(io.ErrClosedPipe, e)
+		// reading stopped soon after the first failed write
+		s.Greater(src.n, 90000)
+		s.Equal(0, w.after)
+	}
+}
+
+func (s *FormatterTestSuite) TestFormatter_PartialOutput() {
+	act := bytes.Buffer{}
+	e := djs.Indent(&act, strings.NewReader(`{"a": [1, 2, x]}`), "", " ")
+	s.Equal(djs.InvalidJsonTokenPtrError{Pos: 13}, e)
+	// tokens before the error written to dst
+	s.Equal("{\n \"a\": [\n  1,\n  2", act.String())
+}
@@ -78,3 +78,53 @@ func TestJsonStructPointer_ArrayOps(t *testing.T) {
 	s.SetFactory(JsonStructPointerFactory)
 	suite.Run(t, s)
 }
+
+//--------------------------------------------------------------------------------------------------------------------
+func TestJsonStructCst_GeneralOpsTestSuite(t *testing.T) {
+	s := new(GeneralOpsTestSuite)
+	s.SetFactory(JsonStructCstFactory)
+	suite.Run(t, s)
+}
+
+func TestJsonStructCst_PrimitiveOpsTestSuite(t *testing.T) {
+	s := new(PrimitiveOpsTestSuite)
+	s.SetFactory(JsonStructCstFactory)
+	suite.Run(t, s)
+}
+
+func TestJsonStructCst_ObjectOps(t *testing.T) {
+	s := new(ObjectOpsTestSuite)
+	s.SetFactory(JsonStructCstFactory)
+	suite.Run(t, s)
+}
+
+func TestJsonStructCst_ArrayOps(t *testing.T) {
+	s := new(ArrayOpsTestSuite)
+	s.SetFactory(JsonStructCstFactory)
+	suite.Run(t, s)
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+func TestJsonStructLazy_GeneralOpsTestSuite(t *testing.T) {
+	s := new(GeneralOpsTestSuite)
+	s.SetFactory(JsonStructLazyFactory)
+	suite.Run(t, s)
+}
+
+func TestJsonStructLazy_PrimitiveOpsTestSuite(t *testing.T) {
+	s := new(PrimitiveOpsTestSuite)
+	s.SetFactory(JsonStructLazyFactory)
+	suite.Run(t, s)
+}
+
+func TestJsonStructLazy_ObjectOps(t *testing.T) {
+	s := new(ObjectOpsTestSuite)
+	s.SetFactory(JsonStructLazyFactory)
+	suite.Run(t, s)
+}
+
+func TestJsonStructLazy_ArrayOps(t *testing.T) {
+	s := new(ArrayOpsTestSuite)
+	s.SetFactory(JsonStructLazyFactory)
+	suite.Run(t, s)
+}
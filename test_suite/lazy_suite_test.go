@@ -0,0 +1,164 @@
+package test_suite
+
+import (
+	"bytes"
+	"encoding/json"
+	djs "github.com/Pencroff/JsonStruct"
+	tl "github.com/Pencroff/JsonStruct/tool"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJStruct_Lazy(t *testing.T) {
+	s := new(LazyTestSuite)
+	suite.Run(t, s)
+}
+
+type LazyTestSuite struct {
+	suite.Suite
+}
+
+const lazyDoc = `{ "id": 7, "user": {"name": "Ann", "tags": [ "a" , "b" ]},
+	"items": [1, 2.50, {"x": null}], "raw": "A" }`
+
+func (s *LazyTestSuite) TestLazy_Values() {
+	v, e := djs.JStructParseLazy([]byte(lazyDoc), djs.ParseOptions{})
+	s.NoError(e)
+	s.True(v.IsObject())
+	s.Equal(4, v.Size())
+	s.ElementsMatch([]string{"id", "user", "items", "raw"}, v.Keys())
+	s.Equal(int64(7), v.GetKey("id").Int())
+	s.Equal("A", v.GetKey("raw").String())
+	user := v.GetKey("user")
+	s.True(user.IsObject())
+	s.Equal("Ann", user.GetKey("name").String())
+	s.Equal("b", user.GetKey("tags").GetIndex(1).String())
+	items := v.GetKey("items")
+	s.Equal(3, items.Size())
+	s.Equal(2.5, items.GetIndex(1).Float())
+	s.True(items.GetIndex(2).GetKey("x").IsNull())
+
+	v, e = djs.JStructParseLazy([]byte(` -12 `), djs.ParseOptions{})
+	s.NoError(e)
+	s.Equal(int64(-12), v.Int())
+	v, e = djs.JStructParseLazy([]byte(`{a: [1, /* c */ 'x',], b: {},}`), djs.ParseOptions{Relaxed: true})
+	s.NoError(e)
+	s.Equal("x", v.GetKey("a").GetIndex(1).String())
+	s.Equal(0, v.GetKey("b").Size())
+}
+
+func (s *LazyTestSuite) TestLazy_Verbatim() {
+	canada, e := tl.ReadGzip("../benchmark/data/canada.json.gz")
+	s.NoError(e)
+	for _, in := range []string{
+		lazyDoc,
+		`[ 1.0E+2 , "é", [ ], { } ]`,
+		`"2015-05-14T12:34:56.379+02:00"`,
+		string(canada),
+	} {
+		v, e := djs.JStructParseLazy([]byte(in), djs.ParseOptions{})
+		s.NoError(e, in)
+		// whitespaces around root value are not part of it
+		in = strings.TrimSpace(in)
+		b, e := v.MarshalJSON()
+		s.NoError(e)
+		s.Equal(in, string(b))
+		// loaded but untouched containers keep source text
+		v.Value()
+		b, _ = v.MarshalJSON()
+		s.Equal(in, string(b))
+	}
+}
+
+func (s *LazyTestSuite) TestLazy_Edit() {
+	v, e := djs.JStructParseLazy([]byte(lazyDoc), djs.ParseOptions{})
+	s.NoError(e)
+	v.GetKey("items").GetIndex(2).GetKey("x").SetBool(true)
+	b, _ := v.MarshalJSON()
+	s.Equal(`{"id":7,"items":[1,2.50,{"x":true}],"raw":"A","user":{"name": "Ann", "tags": [ "a" , "b" ]}}`, string(b))
+
+	s.NoError(v.GetKey("user").GetKey("tags").Push(3))
+	v.RemoveKey("items")
+	b, _ = v.MarshalJSON()
+	s.Equal(`{"id":7,"raw":"A","user":{"name":"Ann","tags":["a","b",3]}}`, string(b))
+
+	js := JsonStructFactory()
+	js.AsArray()
+	s.NoError(js.Push("y"))
+	s.NoError(v.SetKey("id", js))
+	b, _ = v.MarshalJSON()
+	s.Equal(`{"id":["y"],"raw":"A","user":{"name":"Ann","tags":["a","b",3]}}`, string(b))
+}
+
+func (s *LazyTestSuite) TestLazy_Errors() {
+	_, e := djs.JStructParseLazy([]byte(`{"a": [1, 2}`), djs.ParseOptions{})
+	s.Error(e)
+	_, e = djs.JStructParseLazy([]byte(`[1,2`), djs.ParseOptions{})
+	s.Equal(djs.InvalidJsonPtrError{Err: io.EOF, Pos: 3}, e)
+	_, e = djs.JStructParseLazy([]byte(` `), djs.ParseOptions{})
+	s.Error(e)
+	_, e = djs.JStructParseLazy([]byte(`[[[1]]]`), djs.ParseOptions{MaxDepth: 2})
+	s.ErrorIs(e, djs.MaxDepthExceededError)
+
+	v := djs.JsonStructLazy{}
+	s.NoError(v.UnmarshalJSON([]byte(`{"a":[true]}`)))
+	s.True(v.GetKey("a").GetIndex(0).Bool())
+}
+
+func (s *LazyTestSuite) TestLazy_SerializeOptions() {
+	v, e := djs.JStructParseLazy([]byte(`{a:{'b':NaN, c:[1,2,],/*x*/},}`), djs.ParseOptions{Relaxed: true})
+	s.NoError(e)
+	_, e = v.MarshalJSON()
+	s.ErrorIs(e, djs.NonFiniteFloatError)
+	buf := &bytes.Buffer{}
+	s.NoError(djs.JStructSerializeWithOptions(v, buf, djs.SerializeOptions{NonFinite: djs.NonFiniteNull}))
+	s.Equal(`{"a":{"b":null,"c":[1,2]}}`, buf.String())
+	s.True(json.Valid(buf.Bytes()))
+
+	v, e = djs.JStructParseLazy([]byte(`{'s': "é", "n": 0x10}`), djs.ParseOptions{Relaxed: true})
+	s.NoError(e)
+	b, e := v.MarshalJSON()
+	s.NoError(e)
+	s.Equal(`{"n":16,"s":"é"}`, string(b))
+
+	v, e = djs.JStructParseLazy([]byte(`{"s": "é", "f": 1e300}`), djs.ParseOptions{})
+	s.NoError(e)
+	buf.Reset()
+	s.NoError(djs.JStructSerializeWithOptions(v, buf, djs.SerializeOptions{Escape: djs.EscapeASCII, Float: djs.FloatExponent}))
+	s.Equal(`{"f":1e+300,"s":"\u00e9"}`, buf.String())
+	// default options keep source text
+	buf.Reset()
+	s.NoError(djs.JStructSerializeWithOptions(v, buf, djs.SerializeOptions{}))
+	s.Equal(`{"s": "é", "f": 1e300}`, buf.String())
+}
+
+func (s *LazyTestSuite) TestLazy_ParseOptions() {
+	data := []byte(`{"at": "2015-05-14T12:34:56Z", "deep": [{"at": "2015-05-14T12:34:56Z", "day": "2015-05-14",
+		"ts": 1431599696, "bin": "AAEC"}, 1431599696]}`)
+	v, e := djs.JStructParseLazy(data, djs.ParseOptions{NoTimeDetection: true})
+	s.NoError(e)
+	s.True(v.GetKey("at").IsString())
+	item := v.GetKey("deep").GetIndex(0)
+	s.True(item.GetKey("at").IsString())
+	s.Equal("2015-05-14T12:34:56Z", item.GetKey("at").String())
+
+	v, e = djs.JStructParseLazy(data, djs.ParseOptions{
+		TimeLayouts:   []string{djs.TimeLayoutDate},
+		TimeEpochKeys: map[string]djs.EpochUnit{"ts": djs.EpochSeconds},
+		BinaryPaths:   []string{"/deep/0/bin"},
+	})
+	s.NoError(e)
+	item = v.GetKey("deep").GetIndex(0)
+	s.True(item.GetKey("at").IsTime())
+	s.Equal(time.Date(2015, 5, 14, 0, 0, 0, 0, time.UTC), item.GetKey("day").Time())
+	s.Equal(time.Date(2015, 5, 14, 10, 34, 56, 0, time.UTC), item.GetKey("ts").Time())
+	s.True(v.GetKey("deep").GetIndex(1).IsInt())
+	s.Equal([]byte{0, 1, 2}, item.GetKey("bin").(djs.BinaryOps).Bytes())
+	// untouched values keep source text
+	b, e := v.MarshalJSON()
+	s.NoError(e)
+	s.Equal(string(data), string(b))
+}
@@ -25,6 +25,11 @@ func (p *MockedParser) JStructParseFn(rd io.Reader, v djs.JStructOps) error {
 	return args.Error(0)
 }
 
+func (p *MockedParser) JStructParseBytesFn(b []byte, v djs.JStructOps) error {
+	args := p.Called(b, v)
+	return args.Error(0)
+}
+
 func (p *MockedParser) JStructSerializeFn(v djs.JStructOps, wr io.Writer) error {
 	args := p.Called(v, wr)
 	return args.Error(0)
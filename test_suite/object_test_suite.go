@@ -2,11 +2,16 @@ package test_suite
 
 import (
 	djs "github.com/Pencroff/JsonStruct"
+	"github.com/Pencroff/JsonStruct/helper"
 	tl "github.com/Pencroff/JsonStruct/tool"
 	"github.com/stretchr/testify/suite"
+	"testing"
 	"time"
 )
 
+// genericLevel is named type converted by reflection
+type genericLevel int
+
 type ObjectOpsTestSuite struct {
 	suite.Suite
 	factory func() djs.JStructOps
@@ -173,3 +178,135 @@ func (s *ObjectOpsTestSuite) TestKeys() {
 		s.Equal(0, len(v.Keys()))
 	}
 }
+
+func (s *ObjectOpsTestSuite) TestGenericGet() {
+	tm := time.Date(2015, 5, 14, 12, 34, 56, 0, time.UTC)
+	arr := s.factory()
+	arr.AsArray()
+	arr.Push(int64(1))
+	arr.Push(int64(2))
+	arr.Push(int64(300))
+	counts := s.factory()
+	counts.AsObject()
+	counts.SetKey("a", int64(1))
+	counts.SetKey("b", int64(2))
+	obj := s.factory()
+	obj.AsObject()
+	obj.SetKey("n", 3.5)
+	obj.SetKey("s", "str")
+	obj.SetKey("t", tm)
+	obj.SetKey("counts", counts)
+	s.js.AsObject()
+	s.js.SetKey("arr", arr)
+	s.js.SetKey("obj", obj)
+	s.js.SetKey("flag", true)
+	s.js.SetKey("big", helper.MaxUint)
+	s.js.SetKey("huge", 1e300)
+
+	tbl := []struct {
+		idx  string
+		get  func() (interface{}, bool)
+		res  interface{}
+		isOk bool
+	}{
+		{"int", func() (interface{}, bool) { return djs.Get[int](s.js, "arr", 1) }, 2, true},
+		{"int8 overflow", func() (interface{}, bool) { return djs.Get[int8](s.js, "arr", 2) }, int8(0), false},
+		{"int32", func() (interface{}, bool) { return djs.Get[int32](s.js, "arr", 2) }, int32(300), true},
+		{"int of float", func() (interface{}, bool) { return djs.Get[int](s.js, "obj", "n") }, 0, false},
+		{"int64 of big", func() (interface{}, bool) { return djs.Get[int64](s.js, "big") }, int64(0), false},
+		{"uint64", func() (interface{}, bool) { return djs.Get[uint64](s.js, "big") }, helper.MaxUint, true},
+		{"uint8 overflow", func() (interface{}, bool) { return djs.Get[uint8](s.js, "arr", 2) }, uint8(0), false},
+		{"uint16", func() (interface{}, bool) { return djs.Get[uint16](s.js, "arr", 2) }, uint16(300), true},
+		{"named int", func() (interface{}, bool) { return djs.Get[genericLevel](s.js, "arr", 2) }, genericLevel(300), true},
+		{"float32", func() (interface{}, bool) { return djs.Get[float32](s.js, "obj", "n") }, float32(3.5), true},
+		{"float32 overflow", func() (interface{}, bool) { return djs.Get[float32](s.js, "huge") }, float32(0), false},
+		{"float64 of int", func() (interface{}, bool) { return djs.Get[float64](s.js, "arr", 0) }, 1.0, true},
+		{"string", func() (interface{}, bool) { return djs.Get[string](s.js, "obj", "s") }, "str", true},
+		{"string of number", func() (interface{}, bool) { return djs.Get[string](s.js, "obj", "n") }, "", false},
+		{"time", func() (interface{}, bool) { return djs.Get[time.Time](s.js, "obj", "t") }, tm, true},
+		{"bool", func() (interface{}, bool) { return djs.Get[bool](s.js, "flag") }, true, true},
+		{"slice", func() (interface{}, bool) { return djs.Get[[]int](s.js, "arr") }, []int{1, 2, 300}, true},
+		{"slice overflow", func() (interface{}, bool) { return djs.Get[[]int8](s.js, "arr") }, []int8(nil), false},
+		{"map", func() (interface{}, bool) { return djs.Get[map[string]int](s.js, "obj", "counts") }, map[string]int{"a": 1, "b": 2}, true},
+		{"map mismatch", func() (interface{}, bool) { return djs.Get[map[string]int](s.js, "obj") }, map[string]int(nil), false},
+		{"missing key", func() (interface{}, bool) { return djs.Get[int](s.js, "none", 0) }, 0, false},
+		{"negative index", func() (interface{}, bool) { return djs.Get[int](s.js, "arr", -1) }, 0, false},
+		{"index out of range", func() (interface{}, bool) { return djs.Get[int](s.js, "arr", 3) }, 0, false},
+		{"key of array", func() (interface{}, bool) { return djs.Get[int](s.js, "arr", "a") }, 0, false},
+		{"invalid path", func() (interface{}, bool) { return djs.Get[int](s.js, "arr", 1.0) }, 0, false},
+	}
+	for _, el := range tbl {
+		res, ok := el.get()
+		s.Equal(el.res, res, "#%s", el.idx)
+		s.Equal(el.isOk, ok, "#%s", el.idx)
+	}
+
+	entries, ok := djs.Get[map[string]djs.JStructOps](s.js, "obj")
+	s.Equal(true, ok)
+	s.Equal(4, len(entries))
+	s.Equal(3.5, entries["n"].Float())
+	v, ok := djs.As[djs.JStructOps](s.js)
+	s.Equal(true, ok)
+	s.Same(s.js, v)
+	_, ok = djs.As[int](nil)
+	s.Equal(false, ok)
+	// scalars converted without reflection
+	n := s.js.GetKey("arr").GetIndex(2)
+	str := s.js.GetKey("obj").GetKey("s")
+	s.Equal(0.0, testing.AllocsPerRun(10, func() {
+		djs.As[int16](n)
+		djs.As[float64](n)
+		djs.As[string](str)
+	}))
+
+	s.Equal("str", djs.GetOr(s.js, "def", "obj", "s"))
+	s.Equal("def", djs.GetOr(s.js, "def", "obj", "n"))
+	s.Equal("def", djs.GetOr(s.js, "def", "none"))
+	s.Equal(300, djs.MustGet[int](s.js, "arr", 2))
+	s.PanicsWithValue(djs.PathNotFoundError, func() { djs.MustGet[int](s.js, "none") })
+	s.PanicsWithValue(djs.ConversionError, func() { djs.MustGet[int](s.js, "obj", "s") })
+}
+
+func (s *ObjectOpsTestSuite) TestForEachKey() {
+	it, ok := s.js.(djs.IterOps)
+	s.Require().True(ok)
+	calls := 0
+	it.ForEachKey(func(string, djs.JStructOps) bool {
+		calls++
+		return true
+	})
+	s.Equal(0, calls)
+	s.js.AsObject()
+	exp := map[string]int64{"a": 1, "b": 2, "c": 3}
+	for k, v := range exp {
+		s.js.SetKey(k, v)
+	}
+	res := map[string]int64{}
+	it.ForEachKey(func(k string, v djs.JStructOps) bool {
+		res[k] = v.Int()
+		return true
+	})
+	s.Equal(exp, res)
+	res = map[string]int64{}
+	for k, v := range it.Entries() {
+		res[k] = v.Int()
+	}
+	s.Equal(exp, res)
+	// early exit
+	calls = 0
+	it.ForEachKey(func(string, djs.JStructOps) bool {
+		calls++
+		return false
+	})
+	s.Equal(1, calls)
+	calls = 0
+	for range it.Entries() {
+		calls++
+		break
+	}
+	s.Equal(1, calls)
+	// objects have no items
+	for range it.All() {
+		s.Fail("object iterated as array")
+	}
+}
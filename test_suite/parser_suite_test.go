@@ -1,10 +1,21 @@
 package test_suite
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	djs "github.com/Pencroff/JsonStruct"
+	tl "github.com/Pencroff/JsonStruct/tool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 type ParserTestCase struct {
@@ -49,12 +60,486 @@ func (s *ParserTestSuite) TestParsing_PrimitiveValues() {
 			assert.True(t, js.IsBool(), "%s IsBool() != true", el.idx)
 			assert.False(t, js.Bool(), "%s Bool() != false", el.idx)
 		}},
+		{"int", []byte(`-9223372036854775808`), nil, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {
+			assert.True(t, js.IsInt(), "%s IsInt() != true", el.idx)
+			assert.Equal(t, int64(-9223372036854775808), js.Int(), "%s Int()", el.idx)
+		}},
+		{"uint", []byte(`18446744073709551615`), nil, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {
+			assert.True(t, js.IsUint(), "%s IsUint() != true", el.idx)
+			assert.Equal(t, uint64(18446744073709551615), js.Uint(), "%s Uint()", el.idx)
+		}},
+		{"float", []byte(`-1.5e-3`), nil, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {
+			assert.True(t, js.IsFloat(), "%s IsFloat() != true", el.idx)
+			assert.Equal(t, -1.5e-3, js.Float(), "%s Float()", el.idx)
+		}},
+		{"big_int", []byte(`18446744073709551616`), nil, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {
+			assert.True(t, js.IsFloat(), "%s IsFloat() != true", el.idx)
+			assert.Equal(t, 18446744073709551616.0, js.Float(), "%s Float()", el.idx)
+		}},
+		{"string", []byte(`"a\"\\\/\b\f\n\r\t\u00e9\ud834\udd1e"`), nil, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {
+			assert.True(t, js.IsString(), "%s IsString() != true", el.idx)
+			assert.Equal(t, "a\"\\/\b\f\n\r\té𝄞", js.String(), "%s String()", el.idx)
+		}},
+		{"time", []byte(`"2015-05-14T12:34:56.379+02:00"`), nil, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {
+			tm := time.Date(2015, 5, 14, 12, 34, 56, 379000000, time.FixedZone("", 2*60*60))
+			assert.True(t, js.IsTime(), "%s IsTime() != true", el.idx)
+			assert.True(t, tm.Equal(js.Time()), "%s Time() %v != %v", el.idx, js.Time(), tm)
+		}},
 	}
 	for _, el := range testCases {
 		RunParserTestCase(s, el)
 	}
 }
 
+func (s *ParserTestSuite) TestParsing_Containers() {
+	testCases := []ParserTestCase{
+		{"array", []byte(` [ 1, "a", [], {} ] `), nil, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {
+			assert.True(t, js.IsArray(), "%s IsArray() != true", el.idx)
+			assert.Equal(t, 4, js.Size(), "%s Size()", el.idx)
+			assert.Equal(t, int64(1), js.GetIndex(0).Int(), "%s [0]", el.idx)
+			assert.Equal(t, "a", js.GetIndex(1).String(), "%s [1]", el.idx)
+			assert.True(t, js.GetIndex(2).IsArray(), "%s [2] IsArray() != true", el.idx)
+			assert.Equal(t, 0, js.GetIndex(2).Size(), "%s [2] Size()", el.idx)
+			assert.True(t, js.GetIndex(3).IsObject(), "%s [3] IsObject() != true", el.idx)
+		}},
+		{"object", []byte(`{"a":{"b":[true,null]},"c\n":-0.5}`), nil, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {
+			assert.True(t, js.IsObject(), "%s IsObject() != true", el.idx)
+			assert.ElementsMatch(t, []string{"a", "c\n"}, js.Keys(), "%s Keys()", el.idx)
+			arr := js.GetKey("a").GetKey("b")
+			assert.Equal(t, 2, arr.Size(), "%s a.b Size()", el.idx)
+			assert.True(t, arr.GetIndex(0).Bool(), "%s a.b[0]", el.idx)
+			assert.True(t, arr.GetIndex(1).IsNull(), "%s a.b[1]", el.idx)
+			assert.Equal(t, -0.5, js.GetKey("c\n").Float(), "%s c", el.idx)
+		}},
+	}
+	for _, el := range testCases {
+		RunParserTestCase(s, el)
+	}
+}
+
+func (s *ParserTestSuite) TestParsing_InvalidJson() {
+	testCases := []ParserTestCase{
+		{"empty", []byte(``), djs.InvalidJsonError{Err: io.EOF}, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {}},
+		{"unclosed", []byte(`[1,2`), djs.InvalidJsonPtrError{Err: io.EOF, Pos: 3}, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {}},
+		{"trailing", []byte(`{} {}`), djs.InvalidJsonTokenPtrError{Pos: 3}, func(t *testing.T, el ParserTestCase, js djs.JStructOps) {}},
+	}
+	for _, el := range testCases {
+		RunParserTestCase(s, el)
+	}
+}
+
+func (s *ParserTestSuite) TestParsing_SameForReaderAndBytes() {
+	data := []byte(`{"a":[1,-2.5,"x\\ty",true,null,{}],"b":"2015-05-14T12:34:56.379+02:00"}`)
+	js := s.factory()
+	s.NoError(djs.JStructParse(bytes.NewReader(data), js))
+	bjs := s.factory()
+	s.NoError(djs.JStructParseBytes(data, bjs))
+	aliasJs := s.factory()
+	s.NoError(djs.JStructParseBytesWithOptions(data, aliasJs, djs.ParseOptions{AliasInput: true}))
+	for _, v := range []djs.JStructOps{bjs, aliasJs} {
+		s.ElementsMatch(js.Keys(), v.Keys())
+		s.True(js.GetKey("b").Time().Equal(v.GetKey("b").Time()))
+		arr := js.GetKey("a")
+		s.Equal(arr.Size(), v.GetKey("a").Size())
+		for i := 0; i < arr.Size()-1; i++ {
+			s.Equal(arr.GetIndex(i).Value(), v.GetKey("a").GetIndex(i).Value(), "idx: %d", i)
+		}
+	}
+}
+
+func (s *ParserTestSuite) TestParsing_Valid() {
+	files, e := filepath.Glob("../benchmark/data/jsonchecker/*.json")
+	s.NoError(e)
+	roundtrip, e := filepath.Glob("../benchmark/data/roundtrip/*.json")
+	s.NoError(e)
+	files = append(files, roundtrip...)
+	s.NotEmpty(files)
+	// encoding/json limits nesting by 10000 levels
+	deep := strings.Repeat(`[{"a":`, 4000) + `1` + strings.Repeat(`}]`, 4000)
+	grammar := []string{`12`, `-`, `-01`, `1.`, `.5`, `1e`, `1E+`, `-0.0e-0`, `1.5E+10`, `nul`, `truex`,
+		`"\u12"`, `"\u12G4"`, `"\x"`, `"a` + "\t" + `"`, `"a\/\"\\"`, `"é\ud834\udd1e"`, `[1,]`, `[,1]`, `[1 2]`,
+		`{"a" 1}`, `{"a":1 "b":2}`, `{"a":1,"b":}`, `{1:2}`, `{"a":[}`, `[{]}`, ` [ ] `, "[\n1\r,\t2]", `[`, `]`,
+		deep, deep[1:], deep + `]`}
+	for _, in := range grammar {
+		s.Equal(json.Valid([]byte(in)), djs.Valid([]byte(in)), in)
+	}
+	inputs := [][]byte{[]byte(``), []byte(` `), []byte(`[1,2`), []byte(`{} {}`), []byte(`{"a":[1,{"b":null}]} `),
+		[]byte(` "x" `), []byte(`[[]]`), []byte(`{"a":1,}`)}
+	for _, in := range grammar {
+		inputs = append(inputs, []byte(in))
+	}
+	for _, f := range files {
+		b, e := os.ReadFile(f)
+		s.NoError(e)
+		inputs = append(inputs, b)
+	}
+	// Valid has own grammar checker, ValidReader and parsers share tokenizer, all give the same verdict
+	for _, b := range inputs {
+		valid := djs.JStructParseBytes(b, s.factory()) == nil
+		s.Equal(valid, djs.Valid(b), string(b))
+		e := djs.ValidReader(bytes.NewReader(b))
+		s.Equal(valid, e == nil, string(b))
+		s.Equal(djs.JStructParse(bytes.NewReader(b), s.factory()), e, string(b))
+	}
+	s.True(djs.Valid([]byte(strings.Repeat(`[`, 1e6) + strings.Repeat(`]`, 1e6))))
+}
+
+func (s *ParserTestSuite) TestParsing_AliasInput() {
+	data := []byte(`["abc","a\tb"]`)
+	js := s.factory()
+	s.NoError(djs.JStructParseBytesWithOptions(data, js, djs.ParseOptions{AliasInput: true}))
+	s.Equal("abc", js.GetIndex(0).String())
+	s.Equal("a\tb", js.GetIndex(1).String())
+	data[2] = 'x'
+	// string without escape sequences points to input
+	s.Equal("xbc", js.GetIndex(0).String())
+	s.Equal("a\tb", js.GetIndex(1).String())
+
+	data = []byte(`["abc"]`)
+	js = s.factory()
+	s.NoError(djs.JStructParseBytes(data, js))
+	data[2] = 'x'
+	s.Equal("abc", js.GetIndex(0).String())
+}
+
+func (s *ParserTestSuite) TestParsing_File() {
+	data, e := tl.ReadGzip("../benchmark/data/canada.json.gz")
+	s.NoError(e)
+	dir := s.T().TempDir()
+	path := filepath.Join(dir, "canada.json")
+	s.NoError(ioutil.WriteFile(path, data, 0644))
+	js := s.factory()
+	s.NoError(djs.ParseFile(path, js))
+	exp := s.factory()
+	s.NoError(djs.UnmarshalJSON(data, exp))
+	s.ElementsMatch(exp.Keys(), js.Keys())
+	s.Equal("FeatureCollection", js.GetKey("type").String())
+	features := js.GetKey("features")
+	s.Equal(exp.GetKey("features").Size(), features.Size())
+	coords := features.GetIndex(0).GetKey("geometry").GetKey("coordinates")
+	s.Equal(
+		exp.GetKey("features").GetIndex(0).GetKey("geometry").GetKey("coordinates").GetIndex(0).GetIndex(0).GetIndex(0).Float(),
+		coords.GetIndex(0).GetIndex(0).GetIndex(0).Float(),
+	)
+
+	path = filepath.Join(dir, "invalid.json")
+	s.NoError(ioutil.WriteFile(path, []byte(`{"a":[1,2}`), 0644))
+	s.ErrorIs(djs.ParseFile(path, s.factory()), djs.InvalidJsonPtrError{Pos: 9})
+
+	path = filepath.Join(dir, "empty.json")
+	s.NoError(ioutil.WriteFile(path, []byte{}, 0644))
+	s.ErrorIs(djs.ParseFile(path, s.factory()), djs.InvalidJsonError{Err: io.EOF})
+
+	s.ErrorIs(djs.ParseFile(filepath.Join(dir, "missing.json"), s.factory()), os.ErrNotExist)
+}
+
+func (s *ParserTestSuite) TestParsing_FileOptions() {
+	dir := s.T().TempDir()
+	path := filepath.Join(dir, "relaxed.json")
+	s.NoError(ioutil.WriteFile(path, []byte("// comment\n{a: [1, 2,], b: '2015-05-14', c: \"x\"}"), 0644))
+	s.Error(djs.ParseFile(path, s.factory()))
+
+	js := s.factory()
+	opts := djs.ParseOptions{Relaxed: true, AliasInput: true, TimeLayouts: []string{djs.TimeLayoutDate}}
+	s.NoError(djs.ParseFileWithOptions(path, js, opts))
+	s.Equal(2, js.GetKey("a").Size())
+	s.Equal(djs.Time, js.GetKey("b").Type())
+	s.Equal(time.Date(2015, 5, 14, 0, 0, 0, 0, time.UTC), js.GetKey("b").Time())
+	// strings copied from released mapping
+	s.Equal("x", js.GetKey("c").String())
+
+	opts = djs.ParseOptions{Relaxed: true, MaxArrayLength: 1}
+	s.ErrorIs(djs.ParseFileWithOptions(path, s.factory(), opts), djs.MaxArrayLengthExceededError)
+}
+
+func (s *ParserTestSuite) TestParsing_ReusedParser() {
+	large := bytes.NewBuffer([]byte(`["`))
+	large.Write(bytes.Repeat([]byte(`x`), 2*djs.JStructScannerPoolBufferLimit))
+	large.WriteString(`"]`)
+	data := []string{`{"a":"b"}`, `[1,{"c":`, large.String(), `"str"`, `[]`, `{"a":"b"}`}
+	for i := 0; i < 3; i++ {
+		for idx, el := range data {
+			js := s.factory()
+			e := djs.JStructParse(bytes.NewReader([]byte(el)), js)
+			switch idx {
+			case 0, 5:
+				s.NoError(e)
+				s.Equal("b", js.GetKey("a").String())
+			case 1:
+				s.ErrorIs(e, djs.InvalidJsonPtrError{Err: io.EOF, Pos: 7})
+			case 2:
+				s.NoError(e)
+				s.Equal(2*djs.JStructScannerPoolBufferLimit, len(js.GetIndex(0).String()))
+			case 3:
+				s.NoError(e)
+				s.Equal("str", js.String())
+			case 4:
+				s.NoError(e)
+				s.Equal(0, js.Size())
+			}
+		}
+	}
+}
+
+func (s *ParserTestSuite) TestParsing_Limits() {
+	limitErr := func(e error, pos int) error {
+		return djs.LimitExceededError{Err: e, Pos: pos}
+	}
+	testCases := []struct {
+		idx  string
+		opts djs.ParseOptions
+		in   string
+		err  error
+	}{
+		{"depth:0", djs.ParseOptions{MaxDepth: 2}, `[{"a":1}]`, nil},
+		{"depth:1", djs.ParseOptions{MaxDepth: 2}, `[[[1]]]`, limitErr(djs.MaxDepthExceededError, 2)},
+		{"depth:2", djs.ParseOptions{MaxDepth: 1}, `{"a":{}}`, limitErr(djs.MaxDepthExceededError, 5)},
+		{"bytes:0", djs.ParseOptions{MaxBytes: 5}, `[1,2]`, nil},
+		{"bytes:1", djs.ParseOptions{MaxBytes: 5}, `[1,2] `, limitErr(djs.MaxBytesExceededError, 5)},
+		{"bytes:2", djs.ParseOptions{MaxBytes: 5}, `"abcdefgh"`, limitErr(djs.MaxBytesExceededError, 5)},
+		{"string:0", djs.ParseOptions{MaxStringLength: 3}, `["abc", "a\n"]`, nil},
+		{"string:1", djs.ParseOptions{MaxStringLength: 3}, `["abcd"]`, limitErr(djs.MaxStringLengthExceededError, 6)},
+		{"string:2", djs.ParseOptions{MaxStringLength: 3}, `{"abcd":1}`, limitErr(djs.MaxStringLengthExceededError, 6)},
+		{"string:3", djs.ParseOptions{MaxStringLength: 3}, `"abc\n"`, limitErr(djs.MaxStringLengthExceededError, 5)},
+		{"number:0", djs.ParseOptions{MaxNumberLength: 3}, `[123, -12, 1.5, 100]`, nil},
+		{"number:1", djs.ParseOptions{MaxNumberLength: 3}, `[1234]`, limitErr(djs.MaxNumberLengthExceededError, 4)},
+		{"number:2", djs.ParseOptions{MaxNumberLength: 3}, `-1.5`, limitErr(djs.MaxNumberLengthExceededError, 3)},
+		{"number:3", djs.ParseOptions{MaxNumberLength: 3}, `1e10`, limitErr(djs.MaxNumberLengthExceededError, 3)},
+		{"keys:0", djs.ParseOptions{MaxKeysPerObject: 2}, `{"a":{"x":1,"y":2},"b":{}}`, nil},
+		{"keys:1", djs.ParseOptions{MaxKeysPerObject: 2}, `{"a":1,"b":2,"c":3}`, limitErr(djs.MaxKeysPerObjectExceededError, 13)},
+		{"array:0", djs.ParseOptions{MaxArrayLength: 2}, `[[1,2],[]]`, nil},
+		{"array:1", djs.ParseOptions{MaxArrayLength: 2}, `[1,[1,2],3]`, limitErr(djs.MaxArrayLengthExceededError, 9)},
+		{"array:2", djs.ParseOptions{MaxArrayLength: 2}, `[1,[1,2,3]]`, limitErr(djs.MaxArrayLengthExceededError, 8)},
+	}
+	for _, el := range testCases {
+		e := djs.JStructParseWithOptions(bytes.NewReader([]byte(el.in)), s.factory(), el.opts)
+		s.Equal(el.err, e, "%s reader", el.idx)
+		e = djs.JStructParseBytesWithOptions([]byte(el.in), s.factory(), el.opts)
+		s.Equal(el.err, e, "%s bytes", el.idx)
+	}
+	e := djs.JStructParseWithOptions(bytes.NewReader([]byte(`[[1]]`)), s.factory(), djs.ParseOptions{MaxDepth: 1})
+	s.ErrorIs(e, djs.MaxDepthExceededError)
+}
+
+func (s *ParserTestSuite) TestParsing_LimitStopsBufferGrowth() {
+	data := bytes.NewBuffer([]byte(`"`))
+	data.Write(bytes.Repeat([]byte(`x`), 1024*1024))
+	data.WriteString(`"`)
+	sc := djs.NewJStructScanner(data)
+	tk := djs.NewJStructTokenizerWithOptions(sc, djs.ParseOptions{MaxStringLength: 1024})
+	e := tk.Next()
+	s.Equal(djs.LimitExceededError{Err: djs.MaxStringLengthExceededError, Pos: 1026}, e)
+	s.Equal(djs.KindUnknown, tk.Kind())
+	s.LessOrEqual(cap(sc.Buffer()), 2*djs.JStructScannerBufferSize)
+	s.Equal(e, tk.Next())
+}
+
+// cancelReader cancels context once n bytes read
+type cancelReader struct {
+	rd     io.Reader
+	n      int
+	read   int
+	cancel context.CancelFunc
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	n, e := r.rd.Read(p)
+	r.read += n
+	if r.read >= r.n {
+		r.cancel()
+	}
+	return n, e
+}
+
+func (s *ParserTestSuite) TestParsing_Context() {
+	data, e := tl.ReadGzip("../benchmark/data/canada.json.gz")
+	s.NoError(e)
+	js := s.factory()
+	s.NoError(djs.JStructParseContext(context.Background(), bytes.NewReader(data), js, djs.ParseOptions{}))
+	s.Equal("FeatureCollection", js.GetKey("type").String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rd := &cancelReader{rd: bytes.NewReader(data), n: 100 * 1024, cancel: cancel}
+	e = djs.JStructParseContext(ctx, rd, s.factory(), djs.ParseOptions{})
+	s.ErrorIs(e, context.Canceled)
+	s.Equal(djs.ContextError{Err: context.Canceled, Pos: rd.read}, e)
+	s.Less(rd.read, len(data))
+
+	e = djs.JStructParseContext(ctx, bytes.NewReader(data), s.factory(), djs.ParseOptions{})
+	s.Equal(djs.ContextError{Err: context.Canceled, Pos: 0}, e)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	e = djs.JStructParseContext(ctx, bytes.NewReader(data), s.factory(), djs.ParseOptions{})
+	s.ErrorIs(e, context.DeadlineExceeded)
+
+	// limits applied
+	e = djs.JStructParseContext(context.Background(), bytes.NewReader(data), s.factory(), djs.ParseOptions{MaxDepth: 3})
+	s.ErrorIs(e, djs.MaxDepthExceededError)
+
+	// in-memory data
+	js = s.factory()
+	s.NoError(djs.JStructParseBytesContext(context.Background(), data, js, djs.ParseOptions{}))
+	s.Equal("FeatureCollection", js.GetKey("type").String())
+	e = djs.JStructParseBytesContext(&cancelAfterContext{Context: context.Background(), n: 10}, data, s.factory(), djs.ParseOptions{})
+	s.ErrorIs(e, context.Canceled)
+	ce := e.(djs.ContextError)
+	s.Greater(ce.Pos, 0)
+	s.Less(ce.Pos, len(data))
+	e = djs.JStructParseBytesContext(ctx, data, s.factory(), djs.ParseOptions{})
+	s.Equal(djs.ContextError{Err: context.DeadlineExceeded, Pos: 0}, e)
+}
+
+// cancelAfterContext reports cancellation after n checks of Err
+type cancelAfterContext struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfterContext) Done() <-chan struct{} {
+	return make(chan struct{})
+}
+
+func (c *cancelAfterContext) Err() error {
+	c.n--
+	if c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func (s *ParserTestSuite) TestParsing_NDJSON() {
+	long := strings.Repeat("x", 10000)
+	data := "{\"a\": 1}\n\n  [1, 2] \r\n\"" + long + "\"\n1 2\n{\"a\":}\ntrue"
+	rd := djs.NewJStructNDJSONReader(strings.NewReader(data), djs.ParseOptions{})
+	js := s.factory()
+	s.NoError(rd.Next(js))
+	s.Equal(int64(1), js.GetKey("a").Int())
+	s.NoError(rd.Next(js))
+	s.Equal(2, js.Size())
+	s.Equal(3, rd.Line())
+	s.NoError(rd.Next(js))
+	s.Equal(long, js.String())
+	e := rd.Next(s.factory())
+	s.Equal(5, e.(djs.NDJSONLineError).Line)
+	e = rd.Next(s.factory())
+	s.Equal(6, e.(djs.NDJSONLineError).Line)
+	s.NoError(rd.Next(js))
+	s.Equal(true, js.Bool())
+	s.Equal(io.EOF, rd.Next(js))
+	s.Equal(io.EOF, rd.Next(js))
+
+	rd = djs.NewJStructNDJSONReader(strings.NewReader("// c\n{a: 'x',}\n/* c */\n"), djs.ParseOptions{Relaxed: true})
+	s.NoError(rd.Next(js))
+	s.Equal("x", js.GetKey("a").String())
+	s.Equal(io.EOF, rd.Next(js))
+	rd = djs.NewJStructNDJSONReader(strings.NewReader("1\n[1, 2, 3]\n"), djs.ParseOptions{MaxArrayLength: 2})
+	s.NoError(rd.Next(js))
+	s.ErrorIs(rd.Next(js), djs.MaxArrayLengthExceededError)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rd = djs.NewJStructNDJSONReaderContext(ctx, strings.NewReader(data), djs.ParseOptions{})
+	s.NoError(rd.Next(js))
+	cancel()
+	s.Equal(djs.ContextError{Err: context.Canceled, Pos: 9}, rd.Next(js))
+}
+
+func (s *ParserTestSuite) TestParsing_DuplicateKeys() {
+	data := []byte(`{"a":1,"b":{"c":[1,{"d":2,"d":[3]}]},"a":{"x":[1]},"a":"last"}`)
+	parse := func(policy djs.DuplicateKeyPolicy) (djs.JStructOps, error) {
+		js := s.factory()
+		e := djs.JStructParseBytesWithOptions(data, js, djs.ParseOptions{DuplicateKeys: policy})
+		return js, e
+	}
+	js, e := parse(djs.DuplicateKeysLastWins)
+	s.NoError(e)
+	s.Equal("last", js.GetKey("a").String())
+	s.Equal(int64(3), js.GetKey("b").GetKey("c").GetIndex(1).GetKey("d").GetIndex(0).Int())
+
+	js, e = parse(djs.DuplicateKeysFirstWins)
+	s.NoError(e)
+	s.Equal(int64(1), js.GetKey("a").Int())
+	s.Equal(int64(2), js.GetKey("b").GetKey("c").GetIndex(1).GetKey("d").Int())
+	s.ElementsMatch([]string{"a", "b"}, js.Keys())
+
+	_, e = parse(djs.DuplicateKeysError)
+	s.Equal(djs.DuplicateKeyError{Path: "/b/c/1/d", Pos: 26}, e)
+
+	js, e = parse(djs.DuplicateKeysCollectAsArray)
+	s.NoError(e)
+	a := js.GetKey("a")
+	s.True(a.IsArray())
+	s.Equal(3, a.Size())
+	s.Equal(int64(1), a.GetIndex(0).Int())
+	s.Equal(int64(1), a.GetIndex(1).GetKey("x").GetIndex(0).Int())
+	s.Equal("last", a.GetIndex(2).String())
+	d := js.GetKey("b").GetKey("c").GetIndex(1).GetKey("d")
+	s.Equal(2, d.Size())
+	s.Equal(int64(2), d.GetIndex(0).Int())
+	s.Equal(int64(3), d.GetIndex(1).GetIndex(0).Int())
+
+	// array value of the first key is kept as element
+	js = s.factory()
+	e = djs.JStructParseWithOptions(bytes.NewReader([]byte(`{"a":[1,2],"a~/":0,"a":3}`)), js,
+		djs.ParseOptions{DuplicateKeys: djs.DuplicateKeysCollectAsArray})
+	s.NoError(e)
+	s.Equal(2, js.GetKey("a").Size())
+	s.Equal(2, js.GetKey("a").GetIndex(0).Size())
+	s.Equal(int64(3), js.GetKey("a").GetIndex(1).Int())
+
+	e = djs.JStructParseWithOptions(bytes.NewReader([]byte(`[{"a~/":0, "a~/":0}]`)), s.factory(),
+		djs.ParseOptions{DuplicateKeys: djs.DuplicateKeysError})
+	s.Equal(djs.DuplicateKeyError{Path: "/0/a~0~1", Pos: 11}, e)
+}
+
+func (s *ParserTestSuite) TestParsing_Relaxed() {
+	data := []byte(`// config
+{
+	unquoted: 'single \'quoted\'',
+	$id_1: 0x1F, /* hex */ neg: -0X10,
+	"trailing": [ +1, .5, 5., -.5, Infinity, -Infinity, NaN, ],
+	'esc': "\x41\v\0",
+	obj: { a: 1, }, // trailing comma
+}
+/* end */`)
+	opts := djs.ParseOptions{Relaxed: true}
+	for _, parse := range []func(js djs.JStructOps) error{
+		func(js djs.JStructOps) error { return djs.JStructParseBytesWithOptions(data, js, opts) },
+		func(js djs.JStructOps) error { return djs.JStructParseWithOptions(bytes.NewReader(data), js, opts) },
+	} {
+		js := s.factory()
+		s.NoError(parse(js))
+		s.Equal("single 'quoted'", js.GetKey("unquoted").String())
+		s.Equal(int64(31), js.GetKey("$id_1").Int())
+		s.Equal(int64(-16), js.GetKey("neg").Int())
+		arr := js.GetKey("trailing")
+		s.Equal(7, arr.Size())
+		s.Equal(int64(1), arr.GetIndex(0).Int())
+		s.Equal(0.5, arr.GetIndex(1).Float())
+		s.Equal(5.0, arr.GetIndex(2).Float())
+		s.Equal(-0.5, arr.GetIndex(3).Float())
+		s.True(math.IsInf(arr.GetIndex(4).Float(), 1))
+		s.True(math.IsInf(arr.GetIndex(5).Float(), -1))
+		s.True(math.IsNaN(arr.GetIndex(6).Float()))
+		s.Equal("A\v\x00", js.GetKey("esc").String())
+		s.Equal(int64(1), js.GetKey("obj").GetKey("a").Int())
+	}
+
+	for _, el := range []string{`[1,,]`, `[,]`, `{,}`, `{a:1,,}`, `[1 /* open`, `0x`, `'abc"`, `{1a:1}`} {
+		e := djs.JStructParseBytesWithOptions([]byte(el), s.factory(), opts)
+		s.Error(e, el)
+	}
+	e := djs.JStructParseBytesWithOptions([]byte(`[1 /* open`), s.factory(), opts)
+	s.Equal(djs.InvalidJsonPtrError{Err: djs.UnterminatedCommentError, Pos: 9}, e)
+
+	// strict mode rejects all extensions
+	for _, el := range []string{`[1,]`, `{"a":1,}`, `// c
+1`, `/* c */ 1`, `'a'`, `{a:1}`, `0x1F`, `+1`, `.5`, `5.`, `Infinity`, `-NaN`, `"\x41"`, `"\v"`} {
+		e := djs.JStructParseBytes([]byte(el), s.factory())
+		s.Error(e, el)
+	}
+}
+
 func RunParserTestCase(s *ParserTestSuite, el ParserTestCase) {
 	s.T().Run(el.idx, func(t *testing.T) {
 		js := s.factory()
@@ -133,3 +618,195 @@ func RunParserTestCase(s *ParserTestSuite, el ParserTestCase) {
 //		s.Equal(v.Value(), s.js.Value(), "%s %v != %v", el.idx, v.Value(), s.js.Value())
 //	}
 //}
+
+func (s *ParserTestSuite) TestParsing_Recover() {
+	for _, el := range []struct {
+		in      string
+		out     string
+		repairs []djs.Repair
+	}{
+		{`{"a":[1,2`, `{"a":[1,2]}`, []djs.Repair{
+			{Kind: djs.RepairCloseContainer, Pos: 9, Text: "]"},
+			{Kind: djs.RepairCloseContainer, Pos: 9, Text: "}"},
+		}},
+		{`{"a": "hel`, `{"a":"hel"}`, []djs.Repair{
+			{Kind: djs.RepairCloseString, Pos: 10, Text: `"`},
+			{Kind: djs.RepairCloseContainer, Pos: 10, Text: "}"},
+		}},
+		{`[1 2 "x" {"b":true}]`, `[1,2,"x",{"b":true}]`, []djs.Repair{
+			{Kind: djs.RepairInsertComma, Pos: 2, Text: ","},
+			{Kind: djs.RepairInsertComma, Pos: 4, Text: ","},
+			{Kind: djs.RepairInsertComma, Pos: 8, Text: ","},
+		}},
+		{`{name: Bob, "age": 3,}`, `{"name":"Bob","age":3}`, []djs.Repair{
+			{Kind: djs.RepairQuoteWord, Pos: 1, Text: "name"},
+			{Kind: djs.RepairQuoteWord, Pos: 7, Text: "Bob"},
+			{Kind: djs.RepairDropToken, Pos: 20, Text: ","},
+		}},
+		{`{"a":1}}garbage`, `{"a":1}`, []djs.Repair{
+			{Kind: djs.RepairDropToken, Pos: 7, Text: "}garbage"},
+		}},
+		{`{"a" 1 "b":, "c"}`, `{"a":1,"b":null,"c":null}`, []djs.Repair{
+			{Kind: djs.RepairInsertColon, Pos: 4, Text: ":"},
+			{Kind: djs.RepairInsertComma, Pos: 6, Text: ","},
+			{Kind: djs.RepairInsertValue, Pos: 11, Text: "null"},
+			{Kind: djs.RepairInsertColon, Pos: 16, Text: ":"},
+			{Kind: djs.RepairInsertValue, Pos: 16, Text: "null"},
+		}},
+		{`[1,,2,] `, `[1,2]`, []djs.Repair{
+			{Kind: djs.RepairDropToken, Pos: 3, Text: ","},
+			{Kind: djs.RepairDropToken, Pos: 5, Text: ","},
+		}},
+		{"[\"line\nbreak\", \"\\x\", 1.5e", `["line\nbreak","x",1.5]`, []djs.Repair{
+			{Kind: djs.RepairEscapeChar, Pos: 6, Text: `\n`},
+			{Kind: djs.RepairEscapeChar, Pos: 16, Text: `\`},
+			{Kind: djs.RepairDropToken, Pos: 24, Text: "e"},
+			{Kind: djs.RepairCloseContainer, Pos: 25, Text: "]"},
+		}},
+		{`{"a":[{"b":tru]}`, `{"a":[{"b":"tru"}]}`, []djs.Repair{
+			{Kind: djs.RepairQuoteWord, Pos: 11, Text: "tru"},
+			{Kind: djs.RepairCloseContainer, Pos: 14, Text: "}"},
+		}},
+		{`{"a":1, #}`, `{"a":1}`, []djs.Repair{
+			{Kind: djs.RepairDropToken, Pos: 6, Text: ","},
+			{Kind: djs.RepairDropToken, Pos: 8, Text: "#"},
+		}},
+		{` "ok" `, `"ok"`, nil},
+		{`[.5, -.5]`, `[0.5,-0.5]`, []djs.Repair{
+			{Kind: djs.RepairInsertZero, Pos: 1, Text: "0"},
+			{Kind: djs.RepairInsertZero, Pos: 6, Text: "0"},
+		}},
+		{`[1.2.3]`, `[1.2]`, []djs.Repair{{Kind: djs.RepairDropToken, Pos: 4, Text: ".3"}}},
+		{`[01]`, `[0]`, []djs.Repair{{Kind: djs.RepairDropToken, Pos: 2, Text: "1"}}},
+		{`[1e5e5]`, `[100000]`, []djs.Repair{{Kind: djs.RepairDropToken, Pos: 4, Text: "e5"}}},
+		{`[--1]`, `[-1]`, []djs.Repair{{Kind: djs.RepairDropToken, Pos: 1, Text: "-"}}},
+		{`[1-2]`, `[1]`, []djs.Repair{{Kind: djs.RepairDropToken, Pos: 2, Text: "-2"}}},
+		{`[1 .5]`, `[1,0.5]`, []djs.Repair{
+			{Kind: djs.RepairInsertComma, Pos: 2, Text: ","},
+			{Kind: djs.RepairInsertZero, Pos: 3, Text: "0"},
+		}},
+		// comma inserted before dropped token removed
+		{"{ur\\[/{1u+ a\t{:", `{"ur":[{"1u":"a"}]}`, []djs.Repair{
+			{Kind: djs.RepairQuoteWord, Pos: 1, Text: "ur"},
+			{Kind: djs.RepairInsertColon, Pos: 3, Text: ":"},
+			{Kind: djs.RepairDropToken, Pos: 3, Text: `\`},
+			{Kind: djs.RepairDropToken, Pos: 5, Text: "/"},
+			{Kind: djs.RepairQuoteWord, Pos: 7, Text: "1u"},
+			{Kind: djs.RepairInsertColon, Pos: 9, Text: ":"},
+			{Kind: djs.RepairDropToken, Pos: 9, Text: "+"},
+			{Kind: djs.RepairQuoteWord, Pos: 11, Text: "a"},
+			{Kind: djs.RepairDropToken, Pos: 13, Text: "{:"},
+			{Kind: djs.RepairCloseContainer, Pos: 15, Text: "}"},
+			{Kind: djs.RepairCloseContainer, Pos: 15, Text: "]"},
+			{Kind: djs.RepairCloseContainer, Pos: 15, Text: "}"},
+		}},
+	} {
+		cst := djs.NewJStructCstNode()
+		repairs, e := djs.JStructParseRecover([]byte(el.in), cst, djs.ParseOptions{})
+		s.NoError(e, el.in)
+		s.Equal(el.out, string(cst.Source()), el.in)
+		s.Equal(el.repairs, repairs, el.in)
+	}
+
+	for _, el := range []string{``, ` # `, `-`} {
+		_, e := djs.JStructParseRecover([]byte(el), s.factory(), djs.ParseOptions{})
+		s.Equal(djs.InvalidJsonError{Err: io.EOF}, e, el)
+	}
+	_, e := djs.JStructParseRecover([]byte(`[[[1`), s.factory(), djs.ParseOptions{MaxDepth: 2})
+	s.ErrorIs(e, djs.MaxDepthExceededError)
+}
+
+func (s *ParserTestSuite) TestParsing_Spans() {
+	data := []byte("{\n  \"a\": [1, \"xy\"],\r\n  \"b\": {\"c\": null},\n  \"d\": [[]]\n}\n")
+	pos := func(offset, line, column int) djs.Position {
+		return djs.Position{Offset: offset, Line: line, Column: column}
+	}
+	for _, parse := range []func(js djs.JStructOps, opts djs.ParseOptions) error{
+		func(js djs.JStructOps, opts djs.ParseOptions) error {
+			return djs.JStructParseBytesWithOptions(data, js, opts)
+		},
+		func(js djs.JStructOps, opts djs.ParseOptions) error {
+			return djs.JStructParseWithOptions(bytes.NewReader(data), js, opts)
+		},
+	} {
+		js := s.factory()
+		spans := djs.SourceSpans{}
+		s.NoError(parse(js, djs.ParseOptions{Spans: spans, MaxDepth: 5}))
+		s.Equal(8, len(spans))
+		s.Equal(djs.Span{Start: pos(0, 1, 1), End: pos(54, 5, 2)}, spans[js])
+		a := js.GetKey("a")
+		s.Equal(djs.Span{Start: pos(9, 2, 8), End: pos(18, 2, 17)}, spans[a])
+		s.Equal(djs.Span{Start: pos(10, 2, 9), End: pos(11, 2, 10)}, spans[a.GetIndex(0)])
+		s.Equal(djs.Span{Start: pos(13, 2, 12), End: pos(17, 2, 16)}, spans[a.GetIndex(1)])
+		b := js.GetKey("b")
+		s.Equal(djs.Span{Start: pos(28, 3, 8), End: pos(39, 3, 19)}, spans[b])
+		s.Equal("3:14-3:18", spans[b.GetKey("c")].String())
+		d := js.GetKey("d")
+		s.Equal("4:8-4:12", spans[d].String())
+		s.Equal("4:9-4:11", spans[d.GetIndex(0)].String())
+	}
+
+	js := s.factory()
+	spans := djs.SourceSpans{}
+	s.NoError(djs.JStructParseBytesWithOptions([]byte("\n\n  -12.5e3 "), js, djs.ParseOptions{Spans: spans}))
+	s.Equal("3:3-3:10", spans[js].String())
+}
+
+func (s *ParserTestSuite) TestParsing_Time() {
+	data := []byte(`{"rfc": "2015-05-14T12:34:56.100+02:00", "date": "2015-05-14", ` +
+		`"web": "Thu, 14 May 2015 12:34:56 GMT", "ts": 1431599696, "ms": 1431599696789, "f": 1431599696.5, ` +
+		`"arr": [1431599696], "n": 12}`)
+	parse := func(opts djs.ParseOptions) djs.JStructOps {
+		js := s.factory()
+		s.NoError(djs.JStructParseBytesWithOptions(data, js, opts))
+		return js
+	}
+	js := parse(djs.ParseOptions{})
+	s.True(js.GetKey("rfc").IsTime())
+	s.True(js.GetKey("date").IsString())
+	s.True(js.GetKey("ts").IsInt())
+
+	js = parse(djs.ParseOptions{NoTimeDetection: true})
+	s.True(js.GetKey("rfc").IsString())
+	s.Equal("2015-05-14T12:34:56.100+02:00", js.GetKey("rfc").String())
+
+	js = parse(djs.ParseOptions{
+		TimeLayouts:   []string{djs.TimeLayoutDate, time.RFC1123},
+		TimeEpochKeys: map[string]djs.EpochUnit{"ts": djs.EpochSeconds, "ms": djs.EpochMillis, "f": djs.EpochSeconds, "arr": djs.EpochSeconds},
+	})
+	s.Equal(time.Date(2015, 5, 14, 0, 0, 0, 0, time.UTC), js.GetKey("date").Time())
+	s.True(js.GetKey("web").IsTime())
+	s.Equal(time.Date(2015, 5, 14, 12, 34, 56, 0, time.UTC), js.GetKey("web").Time().UTC())
+	s.Equal(time.Date(2015, 5, 14, 10, 34, 56, 0, time.UTC), js.GetKey("ts").Time())
+	s.Equal(time.Date(2015, 5, 14, 10, 34, 56, 789e6, time.UTC), js.GetKey("ms").Time())
+	s.Equal(time.Date(2015, 5, 14, 10, 34, 56, 500e6, time.UTC), js.GetKey("f").Time())
+	s.True(js.GetKey("arr").GetIndex(0).IsInt())
+	s.True(js.GetKey("n").IsInt())
+	// original text kept
+	s.Equal("Thu, 14 May 2015 12:34:56 GMT", js.GetKey("web").String())
+	s.Equal("1431599696789", js.GetKey("ms").String())
+	s.Equal("2015-05-14T12:34:56.100+02:00", js.GetKey("rfc").String())
+
+	// RFC3339 detection switched off, layouts still applied
+	js = parse(djs.ParseOptions{NoTimeDetection: true, TimeLayouts: []string{time.RFC3339}})
+	s.True(js.GetKey("rfc").IsTime())
+	s.True(js.GetKey("date").IsString())
+}
+
+func (s *ParserTestSuite) TestParsing_BinaryPaths() {
+	data := []byte(`{"img": "AAEC+/8=", "list": [{"sig": "AAEC-_8="}, {"sig": "AAEC"}, {"sig": "?"}], "s": "AAEC+/8="}`)
+	js := s.factory()
+	s.NoError(djs.JStructParseBytesWithOptions(data, js, djs.ParseOptions{
+		BinaryPaths: []string{"/img", "/list/0/sig", "/list/1/sig", "/list/2/sig"},
+	}))
+	s.Equal(djs.Binary, js.GetKey("img").Type())
+	s.Equal([]byte{0, 1, 2, 0xfb, 0xff}, js.GetKey("img").Value())
+	s.Equal([]byte{0, 1, 2, 0xfb, 0xff}, js.GetKey("list").GetIndex(0).GetKey("sig").Value())
+	s.Equal([]byte{0, 1, 2}, js.GetKey("list").GetIndex(1).GetKey("sig").Value())
+	s.True(js.GetKey("list").GetIndex(2).GetKey("sig").IsString())
+	s.True(js.GetKey("s").IsString())
+
+	js = s.factory()
+	s.NoError(djs.JStructParseWithOptions(bytes.NewReader([]byte(`"AAEC" `)), js, djs.ParseOptions{BinaryPaths: []string{""}}))
+	s.Equal(djs.Binary, js.Type())
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/Pencroff/JsonStruct/helper"
 	tl "github.com/Pencroff/JsonStruct/tool"
 	"github.com/stretchr/testify/suite"
+	"math"
+	"strings"
 	"time"
 )
 
@@ -119,22 +121,25 @@ func (s *PrimitiveOpsTestSuite) TestGetMethods() {
 		// int
 		{"int:0", int64(0), "SetInt", false, 0, 0, 0, emptyTime, "0"},
 		{"int:1", int64(1), "SetInt", true, 1, 1, 1, emptyTime, "1"},
-		{"int:2", int64(-1), "SetInt", true, -1, helper.MaxUint, -1, emptyTime, "-1"},
+		{"int:2", int64(-1), "SetInt", true, -1, 0, -1, emptyTime, "-1"},
 		{"int:3", helper.MaxInt, "SetInt", true, helper.MaxInt, helper.MaxIntUint, float64(helper.MaxInt), emptyTime, fmt.Sprintf("%d", helper.MaxInt)},
-		{"int:4", helper.MinInt, "SetInt", true, helper.MinInt, helper.MinIntUint, float64(helper.MinInt), emptyTime, fmt.Sprintf("%d", helper.MinInt)},
+		{"int:4", helper.MinInt, "SetInt", true, helper.MinInt, 0, float64(helper.MinInt), emptyTime, fmt.Sprintf("%d", helper.MinInt)},
 		// uint
 		{"uint:0", uint64(0), "SetUint", false, 0, 0, 0, emptyTime, "0"},
 		{"uint:1", uint64(1), "SetUint", true, 1, 1, 1, emptyTime, "1"},
-		{"uint:2", helper.MaxUint, "SetUint", true, -1, helper.MaxUint, float64(helper.MaxUint), emptyTime, fmt.Sprintf("%d", helper.MaxUint)},
+		{"uint:2", helper.MaxUint, "SetUint", true, helper.MaxInt, helper.MaxUint, float64(helper.MaxUint), emptyTime, fmt.Sprintf("%d", helper.MaxUint)},
+		{"uint:3", helper.MinIntUint, "SetUint", true, helper.MaxInt, helper.MinIntUint, float64(helper.MinIntUint), emptyTime, fmt.Sprintf("%d", helper.MinIntUint)},
 		// float
 		{"float:0", 0.0, "SetFloat", false, 0, 0, 0, emptyTime, "0"},
 		{"float:1", 1.0, "SetFloat", true, 1, 1, 1, emptyTime, "1"},
-		{"float:2", -1.0, "SetFloat", true, -1, helper.MaxUint, -1, emptyTime, "-1"},
+		{"float:2", -1.0, "SetFloat", true, -1, 0, -1, emptyTime, "-1"},
 		{"float:3", 3.1415926535897932385, "SetFloat", true, 3, 3, 3.141592653589793, emptyTime, "3.141592653589793"},
-		{"float:4", -3.1415926535897932385, "SetFloat", true, -3, helper.MaxUint - 2, -3.141592653589793, emptyTime, "-3.141592653589793"},
+		{"float:4", -3.1415926535897932385, "SetFloat", true, -3, 0, -3.141592653589793, emptyTime, "-3.141592653589793"},
 		{"float:5", 3.1415, "SetFloat", true, 3, 3, 3.1415, emptyTime, "3.1415"},
+		{"float:6", 1e30, "SetFloat", true, helper.MaxInt, helper.MaxUint, 1e30, emptyTime, "1000000000000000000000000000000"},
+		{"float:7", -1e30, "SetFloat", true, helper.MinInt, 0, -1e30, emptyTime, "-1000000000000000000000000000000"},
 		// time
-		{"time:0", emptyTime, "SetTime", true, emptyTime.UnixMilli(), uint64(emptyTime.UnixMilli()), 0, emptyTime, "0001-01-01T00:00:00Z"},
+		{"time:0", emptyTime, "SetTime", true, emptyTime.UnixMilli(), 0, 0, emptyTime, "0001-01-01T00:00:00Z"},
 		{"time:1", tm, "SetTime", true, tm.UnixMilli(), uint64(tm.UnixMilli()), 0, tm, "2015-05-14T12:34:56+02:00"},
 		{"time:2", unixStart, "SetTime", false, 0, 0, 0, unixStart, "1970-01-01T00:00:00Z"},
 		// string
@@ -154,3 +159,181 @@ func (s *PrimitiveOpsTestSuite) TestGetMethods() {
 		s.Equal(el.stringVal, s.js.String(), "#%s %s(%v) => String() = %v != %v", el.idx, el.setMethod, el.val, s.js.String(), el.stringVal)
 	}
 }
+
+func (s *PrimitiveOpsTestSuite) TestNumberConversion() {
+	tbl := []struct {
+		idx      string
+		val      interface{}
+		mode     djs.RoundingMode
+		intVal   int64
+		intOk    bool
+		uintVal  uint64
+		uintOk   bool
+		floatVal float64
+		floatOk  bool
+		safe     bool
+	}{
+		{"int:0", int64(-1), djs.RoundTruncate, -1, true, 0, false, -1, true, true},
+		{"int:1", helper.MaxSafeInt, djs.RoundTruncate, helper.MaxSafeInt, true, uint64(helper.MaxSafeInt), true, float64(helper.MaxSafeInt), true, true},
+		{"int:2", helper.MinSafeInt - 1, djs.RoundTruncate, helper.MinSafeInt - 1, true, 0, false, float64(helper.MinSafeInt - 1), false, false},
+		{"uint:0", uint64(helper.MaxSafeInt + 1), djs.RoundTruncate, helper.MaxSafeInt + 1, true, uint64(helper.MaxSafeInt + 1), true, float64(helper.MaxSafeInt + 1), false, false},
+		{"uint:1", helper.MaxUint, djs.RoundTruncate, helper.MaxInt, false, helper.MaxUint, true, float64(helper.MaxUint), false, false},
+		{"float:0", 2.5, djs.RoundTruncate, 2, true, 2, true, 2.5, true, false},
+		{"float:1", 2.5, djs.RoundNearest, 3, true, 3, true, 2.5, true, false},
+		{"float:2", 2.5, djs.RoundHalfEven, 2, true, 2, true, 2.5, true, false},
+		{"float:3", -2.5, djs.RoundNearest, -3, true, 0, false, -2.5, true, false},
+		{"float:4", -2.5, djs.RoundFloor, -3, true, 0, false, -2.5, true, false},
+		{"float:5", -2.5, djs.RoundCeil, -2, true, 0, false, -2.5, true, false},
+		{"float:6", -0.5, djs.RoundCeil, 0, true, 0, true, -0.5, true, false},
+		{"float:7", 4.0, djs.RoundTruncate, 4, true, 4, true, 4, true, true},
+		{"float:8", 1e19, djs.RoundTruncate, helper.MaxInt, false, 1e19, true, 1e19, true, false},
+		{"float:9", math.Inf(-1), djs.RoundTruncate, helper.MinInt, false, 0, false, math.Inf(-1), true, false},
+		{"string:0", "1", djs.RoundTruncate, 0, false, 0, false, 0, false, false},
+		{"bool:0", true, djs.RoundTruncate, 0, false, 0, false, 0, false, false},
+	}
+	for _, el := range tbl {
+		switch v := el.val.(type) {
+		case bool:
+			s.js.SetBool(v)
+		case int64:
+			s.js.SetInt(v)
+		case uint64:
+			s.js.SetUint(v)
+		case float64:
+			s.js.SetFloat(v)
+		case string:
+			s.js.SetString(v)
+		}
+		i, ok := djs.ToInt(s.js, el.mode)
+		s.Equal(el.intVal, i, "#%s ToInt", el.idx)
+		s.Equal(el.intOk, ok, "#%s ToInt ok", el.idx)
+		u, ok := djs.ToUint(s.js, el.mode)
+		s.Equal(el.uintVal, u, "#%s ToUint", el.idx)
+		s.Equal(el.uintOk, ok, "#%s ToUint ok", el.idx)
+		f, ok := djs.ToFloat(s.js)
+		s.Equal(el.floatVal, f, "#%s ToFloat", el.idx)
+		s.Equal(el.floatOk, ok, "#%s ToFloat ok", el.idx)
+		s.Equal(el.safe, djs.IsSafeInt(s.js), "#%s IsSafeInt", el.idx)
+	}
+}
+
+func (s *PrimitiveOpsTestSuite) TestBinaryOps() {
+	bv, ok := s.js.(djs.BinaryOps)
+	if !ok {
+		s.T().Skip("binary type not supported")
+	}
+	s.Equal(false, bv.IsBinary())
+	s.Nil(bv.Bytes())
+	data := []byte{0, 1, 2, 0xfb, 0xff}
+	bv.SetBytes(data)
+	s.Equal(true, bv.IsBinary())
+	s.Equal(djs.Binary, s.js.Type())
+	s.Equal("Binary", s.js.Type().String())
+	s.Equal(data, bv.Bytes())
+	s.Equal(data, s.js.Value())
+	s.Equal(5, s.js.Size())
+	s.Equal(true, s.js.Bool())
+	s.Equal("AAEC+/8=", s.js.String())
+	s.js.SetString("AAEC+/8=")
+	s.Equal(data, bv.Bytes())
+	s.js.SetString("AAEC-_8=")
+	s.Nil(bv.Bytes())
+}
+
+func (s *PrimitiveOpsTestSuite) TestTimeSourceOps() {
+	tv, ok := s.js.(djs.TimeSourceOps)
+	if !ok {
+		s.T().Skip("time source not supported")
+	}
+	tm := time.Date(2015, 5, 14, 0, 0, 0, 0, time.UTC)
+	tv.SetParsedTime(tm, `"2015-05-14"`)
+	s.Equal(true, s.js.IsTime())
+	s.Equal(tm, s.js.Time())
+	s.Equal(`"2015-05-14"`, tv.TimeSource())
+	var sb strings.Builder
+	s.NoError(djs.JStructSerializeFn(s.js, &sb))
+	s.Equal(`"2015-05-14"`, sb.String())
+	s.js.SetTime(tm)
+	s.Equal("", tv.TimeSource())
+	sb.Reset()
+	s.NoError(djs.JStructSerializeFn(s.js, &sb))
+	s.Equal(`"2015-05-14T00:00:00Z"`, sb.String())
+	tv.SetParsedTime(tm, "1431561600")
+	s.js.SetInt(1)
+	s.Equal("", tv.TimeSource())
+}
+
+func (s *PrimitiveOpsTestSuite) TestStrictOps() {
+	sv, ok := s.js.(djs.StrictOps)
+	if !ok {
+		s.T().Skip("strict accessors not supported")
+	}
+	tm := time.Date(2015, 1, 1, 12, 34, 56, 0, time.UTC)
+	tbl := []struct {
+		val interface{}
+		fn  string
+		res interface{}
+		err error
+	}{
+		{true, "BoolE", true, nil},
+		{false, "BoolE", false, nil},
+		{int64(0), "BoolE", false, djs.TypeMismatchError{Expected: djs.True, Actual: djs.Int}},
+		{int64(-5), "IntE", int64(-5), nil},
+		{uint64(5), "IntE", int64(5), nil},
+		{uint64(helper.MaxUint), "IntE", int64(0), djs.NumberOverflowError},
+		{1.0, "IntE", int64(0), djs.TypeMismatchError{Expected: djs.Int, Actual: djs.Float}},
+		{"1", "IntE", int64(0), djs.TypeMismatchError{Expected: djs.Int, Actual: djs.String}},
+		{nil, "IntE", int64(0), djs.TypeMismatchError{Expected: djs.Int, Actual: djs.Null}},
+		{uint64(helper.MaxUint), "UintE", uint64(helper.MaxUint), nil},
+		{int64(7), "UintE", uint64(7), nil},
+		{int64(-1), "UintE", uint64(0), djs.NumberOverflowError},
+		{"abc", "UintE", uint64(0), djs.TypeMismatchError{Expected: djs.Uint, Actual: djs.String}},
+		{1.5, "FloatE", 1.5, nil},
+		{int64(-3), "FloatE", -3.0, nil},
+		{uint64(3), "FloatE", 3.0, nil},
+		{true, "FloatE", 0.0, djs.TypeMismatchError{Expected: djs.Float, Actual: djs.True}},
+		{"", "StringE", "", nil},
+		{"abc", "StringE", "abc", nil},
+		{int64(0), "StringE", "", djs.TypeMismatchError{Expected: djs.String, Actual: djs.Int}},
+		{tm, "TimeE", tm, nil},
+		{"2015-01-01T12:34:56Z", "TimeE", time.Time{}, djs.TypeMismatchError{Expected: djs.Time, Actual: djs.String}},
+	}
+	for idx, el := range tbl {
+		s.js = s.factory()
+		sv = s.js.(djs.StrictOps)
+		switch v := el.val.(type) {
+		case nil:
+			s.js.SetNull()
+		case bool:
+			s.js.SetBool(v)
+		case int64:
+			s.js.SetInt(v)
+		case uint64:
+			s.js.SetUint(v)
+		case float64:
+			s.js.SetFloat(v)
+		case string:
+			s.js.SetString(v)
+		case time.Time:
+			s.js.SetTime(v)
+		}
+		var res interface{}
+		var e error
+		switch el.fn {
+		case "BoolE":
+			res, e = sv.BoolE()
+		case "IntE":
+			res, e = sv.IntE()
+		case "UintE":
+			res, e = sv.UintE()
+		case "FloatE":
+			res, e = sv.FloatE()
+		case "StringE":
+			res, e = sv.StringE()
+		case "TimeE":
+			res, e = sv.TimeE()
+		}
+		s.Equal(el.res, res, "#%d %s(%v)", idx, el.fn, el.val)
+		s.Equal(el.err, e, "#%d %s(%v)", idx, el.fn, el.val)
+	}
+}
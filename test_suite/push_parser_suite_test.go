@@ -0,0 +1,190 @@
+package test_suite
+
+import (
+	"bytes"
+	djs "github.com/Pencroff/JsonStruct"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"strings"
+	"testing"
+)
+
+type PushParserToken struct {
+	kind  djs.TokenizerKind
+	level djs.TokenizerLevel
+	value string
+}
+
+func TestJStruct_PushParser(t *testing.T) {
+	s := new(PushParserTestSuite)
+	suite.Run(t, s)
+}
+
+type PushParserTestSuite struct {
+	suite.Suite
+}
+
+var pushParserTestCases = []struct {
+	idx string
+	in  []byte
+}{
+	{"push:00", []byte(`null`)},
+	{"push:01", []byte(` true `)},
+	{"push:02", []byte(`-123.456e+7`)},
+	{"push:03", []byte(`"a\"b\\cé𝄞"`)},
+	{"push:04", []byte(`"2015-05-14T12:34:56.379+02:00"`)},
+	{"push:05", []byte(` [ 1, -2.5, "x", true, false, null ] `)},
+	{"push:06", []byte(`{"a":{"b":[1,[2,[]],{}]},"c":"\\\"","d":-0}`)},
+	{"push:07", []byte("{\n\t\"key\" : [ 18446744073709551615 , 1e-3 ]\n}\n")},
+	// Invalid
+	{"push:100", []byte(``)},
+	{"push:101", []byte(`[1,2`)},
+	{"push:102", []byte(`{"a" 1}`)},
+	{"push:103", []byte(`[1,]`)},
+	{"push:104", []byte(`"abc\x"`)},
+	{"push:105", []byte(`{"a":1} x`)},
+	{"push:106", []byte(`[01]`)},
+	{"push:107", []byte(`-`)},
+	{"push:108", []byte(`[ "abc`)},
+}
+
+func (s *PushParserTestSuite) TestPushParser_SameTokensAsTokenizer() {
+	for _, el := range pushParserTestCases {
+		expTokens, expErr := pullTokens(el.in)
+		for size := 1; size <= len(el.in); size++ {
+			tokens, err := pushTokens(el.in, size)
+			s.Equal(expTokens, tokens, "%s chunk size %d", el.idx, size)
+			s.Equal(expErr, err, "%s chunk size %d", el.idx, size)
+		}
+		// split at every byte
+		for i := 0; i < len(el.in); i++ {
+			tokens, err := pushTokens(el.in, i, len(el.in))
+			s.Equal(expTokens, tokens, "%s split at %d", el.idx, i)
+			s.Equal(expErr, err, "%s split at %d", el.idx, i)
+		}
+	}
+}
+
+func (s *PushParserTestSuite) TestPushParser_EmitsTokensBeforeClose() {
+	var tokens []PushParserToken
+	p := djs.NewJStructPushParser(func(kind djs.TokenizerKind, level djs.TokenizerLevel, value []byte) error {
+		tokens = append(tokens, PushParserToken{kind, level, string(value)})
+		return nil
+	})
+	_, e := p.Write([]byte(`[1, "ab`))
+	s.NoError(e)
+	s.Equal([]PushParserToken{
+		{djs.KindLiteral, djs.LevelArray, ""},
+		{djs.KindNumber, djs.LevelValue, "1"},
+	}, tokens)
+	_, e = p.Write([]byte(`c"`))
+	s.NoError(e)
+	s.Equal(2, len(tokens))
+	_, e = p.Write([]byte(`]`))
+	s.NoError(e)
+	s.Equal(PushParserToken{djs.KindString, djs.LevelValueLast, `"abc"`}, tokens[2])
+	s.NoError(p.Close())
+	_, e = p.Write([]byte(` `))
+	s.ErrorIs(e, djs.ClosedPushParserError)
+}
+
+func (s *PushParserTestSuite) TestPushParser_BuildsValue() {
+	data := []byte(`{"a":[1,"b",{"c":null}],"d":3.5}`)
+	js := JsonStructFactory()
+	var res djs.JStructOps
+	p := djs.NewJStructValuePushParser(js, func(v djs.JStructOps) error {
+		res = v
+		return nil
+	})
+	for _, b := range data {
+		_, e := p.Write([]byte{b})
+		s.NoError(e)
+	}
+	// root container completed by last bracket
+	s.Equal(js, res)
+	s.NoError(p.Close())
+	s.Equal(true, js.IsObject())
+	s.Equal(3.5, js.GetKey("d").Float())
+	arr := js.GetKey("a")
+	s.Equal(3, arr.Size())
+	s.Equal(int64(1), arr.GetIndex(0).Int())
+	s.Equal("b", arr.GetIndex(1).String())
+	s.Equal(true, arr.GetIndex(2).GetKey("c").IsNull())
+}
+
+func (s *PushParserTestSuite) TestPushParser_LargeTokens() {
+	str := strings.Repeat(`abc\"é`, 1<<19)
+	num := "0." + strings.Repeat("1234567890", 1<<12)
+	in := []byte(`["` + str + `", ` + num + ` , "x"]`)
+	exp, expErr := pullTokens(in)
+	s.NoError(expErr)
+	for _, size := range []int{1 << 10, 7} {
+		tokens, e := pushTokens(in, size)
+		s.NoError(e)
+		s.Equal(exp, tokens, "chunk size %d", size)
+	}
+}
+
+func (s *PushParserTestSuite) TestPushParser_StickyError() {
+	p := djs.NewJStructPushParser(func(kind djs.TokenizerKind, level djs.TokenizerLevel, value []byte) error {
+		return nil
+	})
+	_, e := p.Write([]byte(`[1 2]`))
+	s.ErrorIs(e, djs.InvalidJsonPtrError{Pos: 3})
+	_, e = p.Write([]byte(`[]`))
+	s.ErrorIs(e, djs.InvalidJsonPtrError{Pos: 3})
+	s.ErrorIs(p.Close(), djs.InvalidJsonPtrError{Pos: 3})
+}
+
+func pullTokens(in []byte) ([]PushParserToken, error) {
+	var tokens []PushParserToken
+	tk := djs.NewJStructTokenizer(djs.NewJStructScanner(bytes.NewReader(in)))
+	for {
+		e := tk.Next()
+		if e == io.EOF {
+			return tokens, nil
+		}
+		if e != nil {
+			return tokens, e
+		}
+		tokens = append(tokens, PushParserToken{tk.Kind(), tk.Level(), string(tk.Value())})
+	}
+}
+
+// pushTokens writes data by chunks of size or split by given positions
+func pushTokens(in []byte, sizes ...int) ([]PushParserToken, error) {
+	var tokens []PushParserToken
+	p := djs.NewJStructPushParser(func(kind djs.TokenizerKind, level djs.TokenizerLevel, value []byte) error {
+		tokens = append(tokens, PushParserToken{kind, level, string(value)})
+		return nil
+	})
+	var chunks [][]byte
+	if len(sizes) == 1 {
+		for i := 0; i < len(in); i += sizes[0] {
+			end := i + sizes[0]
+			if end > len(in) {
+				end = len(in)
+			}
+			chunks = append(chunks, in[i:end])
+		}
+	} else {
+		prev := 0
+		for _, pos := range sizes {
+			chunks = append(chunks, in[prev:pos])
+			prev = pos
+		}
+	}
+	for _, chunk := range chunks {
+		_, e := p.Write(chunk)
+		if e != nil {
+			return tokens, e
+		}
+	}
+	return tokens, p.Close()
+}
+
+func Test_PushParserErrorPosition(t *testing.T) {
+	_, e := pushTokens([]byte(`{"a":[1,2,3],"b":tru}`), 5, 13, 21)
+	assert.ErrorIs(t, e, djs.InvalidJsonPtrError{Pos: 20})
+}
@@ -2,6 +2,7 @@ package test_suite
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	djs "github.com/Pencroff/JsonStruct"
 	tl "github.com/Pencroff/JsonStruct/tool"
@@ -9,6 +10,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"testing/iotest"
 )
 
 func TestJStruct_Scanner(t *testing.T) {
@@ -175,6 +177,142 @@ func (s *ScannerTestSuite) TestScanner_DifferentBuffers() {
 
 }
 
+func (s *ScannerTestSuite) TestBytesScanner_SameAsScanner() {
+	data := []byte(`ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789abcdefghijklmnopqrstuvwxyz`)
+	offsets := []int{1, 2, 3, 5, 8, 13, 21, 34, 55}
+	for _, offset := range offsets {
+		sc := djs.NewJStructScannerWithParam(bytes.NewBuffer(data), 16, 4)
+		bsc := djs.NewJStructBytesScanner(data)
+		for i := 0; i < 3*len(data)/offset+2; i++ {
+			e := sc.Scan(offset)
+			be := bsc.Scan(offset)
+			s.Equal(e, be, "offset:%d step:%d", offset, i)
+			s.Equal(sc.Index(), bsc.Index(), "offset:%d step:%d", offset, i)
+			s.Equal(sc.Current(), bsc.Current(), "offset:%d step:%d", offset, i)
+			if i%2 == 0 {
+				s.Equal(sc.Bytes(), bsc.Bytes(), "offset:%d step:%d", offset, i)
+			}
+		}
+	}
+}
+
+func (s *ScannerTestSuite) TestBytesScanner_EmptyData() {
+	sc := djs.NewJStructBytesScanner([]byte{})
+	s.ErrorIs(sc.Next(), io.EOF)
+	s.Equal(-1, sc.Index())
+	s.Equal(byte(0), sc.Current())
+	s.Equal([]byte{}, sc.Bytes())
+}
+
+func (s *ScannerTestSuite) TestBytesScanner_NoCopy() {
+	data := []byte(`abc`)
+	sc := djs.NewJStructBytesScanner(data)
+	s.NoError(sc.Scan(2))
+	b := sc.Bytes()
+	s.Equal(&data[0], &b[0])
+	s.Equal(2, cap(b))
+	s.NoError(sc.Next())
+	b = sc.Bytes()
+	s.Equal(&data[2], &b[0])
+	s.ErrorIs(sc.Next(), io.EOF)
+	s.Equal(2, sc.Index())
+}
+
+func (s *ScannerTestSuite) TestScanner_Reset() {
+	data := []byte(`ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789abcdefghijklmnopqrstuvwxyz`)
+	e := s.rd.Scan(len(data) + 1)
+	s.ErrorIs(e, io.EOF)
+	size := cap(s.rd.Buffer())
+	s.rd.Reset(bytes.NewBuffer([]byte(`abc`)))
+	s.Equal(-1, s.rd.Index())
+	s.Equal(byte(0), s.rd.Current())
+	s.NoError(s.rd.Scan(3))
+	s.Equal(byte('c'), s.rd.Current())
+	s.Equal([]byte(`abc`), s.rd.Bytes())
+	s.ErrorIs(s.rd.Next(), io.EOF)
+	s.Equal(size, cap(s.rd.Buffer()))
+	s.rd.Reset(bytes.NewBuffer(data))
+	for idx, ch := range data {
+		s.NoError(s.rd.Next())
+		s.Equal(ch, s.rd.Current(), "idx:%d", idx)
+		s.Equal(idx, s.rd.Index())
+	}
+	s.Equal(data, s.rd.Bytes())
+}
+
+func (s *ScannerTestSuite) TestBytesScanner_Reset() {
+	data := []byte(`abc`)
+	sc := djs.NewJStructBytesScanner(data)
+	s.ErrorIs(sc.Scan(4), io.EOF)
+	sc.Reset(bytes.NewBuffer([]byte(`xy`)))
+	s.Equal([]byte(`abc`), data)
+	s.NoError(sc.Scan(2))
+	s.Equal([]byte(`xy`), sc.Bytes())
+	s.ErrorIs(sc.Next(), io.EOF)
+	sc.(*djs.JStructBytesScannerImpl).ResetBytes(data)
+	s.NoError(sc.Next())
+	s.Equal(byte('a'), sc.Current())
+	s.Equal(0, sc.Index())
+}
+
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func (s *ScannerTestSuite) TestScanner_ReaderError() {
+	readErr := errors.New("read error")
+	sc := djs.NewJStructScannerWithParam(&failingReader{[]byte(`abcdefgh`), readErr}, 4, 1)
+	s.NoError(sc.Scan(6))
+	s.Equal(byte('f'), sc.Current())
+	s.ErrorIs(sc.Scan(4), readErr)
+	s.Equal(7, sc.Index())
+	s.Equal(byte('h'), sc.Current())
+	s.Equal([]byte(`abcdefgh`), sc.Bytes())
+	s.ErrorIs(sc.Next(), readErr)
+
+	sc = djs.NewJStructScanner(&failingReader{nil, readErr})
+	s.ErrorIs(sc.Next(), readErr)
+	s.Equal(-1, sc.Index())
+}
+
+func (s *ScannerTestSuite) TestScanner_BoundedBuffer() {
+	data := append(append([]byte(`[`), bytes.Repeat([]byte(`1,`), 1<<20)...), `1]`...)
+	readers := map[string]func() io.Reader{
+		"full": func() io.Reader { return bytes.NewReader(data) },
+		"half": func() io.Reader { return iotest.HalfReader(bytes.NewReader(data)) },
+		"one":  func() io.Reader { return iotest.OneByteReader(bytes.NewReader(data)) },
+	}
+	for name, rd := range readers {
+		sc := djs.NewJStructScanner(rd())
+		tk := djs.NewJStructTokenizer(sc)
+		n := 0
+		for e := tk.Next(); e == nil; e = tk.Next() {
+			n++
+		}
+		s.Equal(1<<20+2, n, name)
+		// free head of buffer reused by refill, only huge token expands buffer
+		s.Equal(djs.JStructScannerBufferSize, cap(sc.Buffer()), name)
+	}
+
+	long := bytes.Repeat([]byte(`x`), 3*djs.JStructScannerBufferSize)
+	sc := djs.NewJStructScanner(bytes.NewReader(append(append([]byte(`["`), long...), `", 1]`...)))
+	tk := djs.NewJStructTokenizer(sc)
+	s.NoError(tk.Next())
+	s.NoError(tk.Next())
+	s.Equal(len(long)+2, len(tk.Value()))
+	s.LessOrEqual(cap(sc.Buffer()), 4*len(long))
+}
+
 func Benchmark_Scanner(b *testing.B) {
 	data, _ := tl.ReadGzip("../benchmark/data/canada.json.gz")
 	data2, _ := tl.ReadGzip("../benchmark/data/large-file.json.gz")
@@ -0,0 +1,257 @@
+package test_suite
+
+import (
+	"fmt"
+	djs "github.com/Pencroff/JsonStruct"
+	tl "github.com/Pencroff/JsonStruct/tool"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJStruct_Tape(t *testing.T) {
+	s := new(TapeTestSuite)
+	suite.Run(t, s)
+}
+
+type TapeTestSuite struct {
+	suite.Suite
+}
+
+// equalOps compares tape value with value of standard parser
+func (s *TapeTestSuite) equalOps(exp, act djs.JStructOps, path string) {
+	if !s.Equal(exp.Type(), act.Type(), path) {
+		return
+	}
+	switch exp.Type() {
+	case djs.Object:
+		s.ElementsMatch(exp.Keys(), act.Keys(), path)
+		for _, k := range exp.Keys() {
+			s.equalOps(exp.GetKey(k), act.GetKey(k), path+"/"+k)
+		}
+	case djs.Array:
+		s.Equal(exp.Size(), act.Size(), path)
+		for i := 0; i < exp.Size(); i++ {
+			s.equalOps(exp.GetIndex(i), act.GetIndex(i), path+"/"+string(rune('0'+i%10)))
+		}
+	case djs.Time:
+		s.True(exp.Time().Equal(act.Time()), path)
+	default:
+		s.Equal(exp.Value(), act.Value(), path)
+	}
+}
+
+func (s *TapeTestSuite) TestTape_SameAsParser() {
+	// escape sequences crossing 64 bytes blocks of structural index
+	bs := strings.Repeat(`\\`, 40)
+	inputs := []string{
+		`null`, ` true `, `false`, `-0`, `12`, `-9223372036854775808`, `18446744073709551615`,
+		`18446744073709551616`, `1.5e-3`, `1E400`, `"a\"b\\\/\b\f\n\r\tA𝄞"`,
+		`"2015-05-14T12:34:56.379+02:00"`, `"2015-05-14T24:34:56Z"`,
+		`[]`, `{}`, `[[[]]]`, ` [ 1 , { "a" : [ null ] } , "x" ] `,
+		`{"a":1,"b":{"c":[true,false,{}],"d":"é𝄞"}}`,
+		`["` + strings.Repeat("x", 62) + `\"", "y"]`,
+		`["` + strings.Repeat("x", 61) + `\\", "y"]`,
+		`["` + bs + `", {"` + bs + `\"` + `":"` + strings.Repeat(`\"`, 50) + `"}]`,
+		`[` + strings.Repeat(`"ab\\\"cd", `, 30) + `0]`,
+	}
+	for _, name := range []string{"canada", "citm_catalog", "twitter"} {
+		b, e := tl.ReadGzip("../benchmark/data/" + name + ".json.gz")
+		s.NoError(e)
+		inputs = append(inputs, string(b))
+	}
+	var reuse *djs.Tape
+	for _, in := range inputs {
+		js := JsonStructFactory()
+		// standard parser expects root primitive to be followed by whitespace
+		s.NoError(djs.JStructParseBytesFn([]byte(in+" "), js), in)
+		t, e := djs.JStructParseTape([]byte(in), reuse)
+		if !s.NoError(e, in) {
+			continue
+		}
+		reuse = t
+		s.equalOps(js, t.Root(), "")
+	}
+}
+
+func (s *TapeTestSuite) TestTape_JsonChecker() {
+	files, e := filepath.Glob("../benchmark/data/jsonchecker/*.json")
+	s.NoError(e)
+	for _, f := range files {
+		b, e := os.ReadFile(f)
+		s.NoError(e)
+		_, e = djs.JStructParseTape(b, nil)
+		name := filepath.Base(f)
+		if strings.HasPrefix(name, "pass") {
+			s.NoError(e, name)
+		}
+		if strings.HasPrefix(name, "fail") && !strings.Contains(name, "EXCLUDE") {
+			s.Error(e, name)
+		}
+	}
+}
+
+func (s *TapeTestSuite) TestTape_Values() {
+	t, e := djs.JStructParseTape([]byte(`{"id": 7, "tags": ["a", "b\n"], "at": "2022-01-02T03:04:05Z",
+		"id": 8, "n": null, "f": 2.5, "u": 18446744073709551615, "e": {}}`), nil)
+	s.NoError(e)
+	v := t.Root()
+	s.True(v.IsObject())
+	s.Equal(7, v.Size())
+	s.Equal([]string{"tags", "at", "id", "n", "f", "u", "e"}, v.Keys())
+	s.Equal(int64(8), v.GetKey("id").Int())
+	s.True(v.HasKey("n"))
+	s.False(v.HasKey("x"))
+	s.Nil(v.GetKey("x"))
+	s.Equal(2, v.GetKey("tags").Size())
+	s.Equal("b\n", v.GetKey("tags").GetIndex(1).String())
+	s.Nil(v.GetKey("tags").GetIndex(2))
+	s.Nil(v.GetIndex(0))
+	s.Equal(time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC), v.GetKey("at").Time())
+	s.True(v.GetKey("n").IsNull())
+	s.Equal(2.5, v.GetKey("f").Float())
+	s.Equal(int64(2), v.GetKey("f").Int())
+	s.Equal(uint64(18446744073709551615), v.GetKey("u").Uint())
+	s.Equal(0, v.GetKey("e").Size())
+	b, e := v.GetKey("tags").(*djs.TapeValue).MarshalJSON()
+	s.NoError(e)
+	s.Equal(`["a","b\n"]`, string(b))
+
+	s.ErrorIs(v.SetKey("a", 1), djs.ReadOnlyError)
+	s.ErrorIs(v.GetKey("tags").Push(1), djs.ReadOnlyError)
+	s.PanicsWithValue(djs.ReadOnlyError, func() { v.GetKey("id").SetInt(1) })
+	s.PanicsWithValue(djs.ReadOnlyError, func() { v.AsArray() })
+	s.NotPanics(func() { v.AsObject() })
+
+	var keys []string
+	for k := range v.Entries() {
+		keys = append(keys, k)
+	}
+	s.Equal(v.Keys(), keys)
+	var items []string
+	v.GetKey("tags").(djs.IterOps).ForEachIndex(func(i int, el djs.JStructOps) bool {
+		items = append(items, el.String())
+		return i < 0
+	})
+	s.Equal([]string{"a"}, items)
+
+	tv := &djs.TapeValue{}
+	s.NoError(tv.UnmarshalJSON([]byte(`[1]`)))
+	s.Equal(int64(1), tv.GetIndex(0).Int())
+}
+
+func (s *TapeTestSuite) TestTape_Errors() {
+	for _, el := range []struct {
+		in  string
+		err error
+	}{
+		{``, djs.InvalidJsonError{Err: io.EOF}},
+		{` `, djs.InvalidJsonError{Err: io.EOF}},
+		{`[1,2`, djs.InvalidJsonPtrError{Err: io.EOF, Pos: 3}},
+		{`"abc`, djs.InvalidJsonPtrError{Err: io.EOF, Pos: 3}},
+		{`[1,]`, djs.InvalidJsonPtrError{Err: djs.InvalidCharacterError, Pos: 3}},
+		{`{"a" 1}`, djs.InvalidJsonTokenPtrError{Pos: 5}},
+		{`{"a":1 "b":2}`, djs.InvalidJsonTokenPtrError{Pos: 7}},
+		{`[1] 2`, djs.InvalidJsonTokenPtrError{Pos: 4}},
+		{`[01]`, djs.InvalidJsonPtrError{Err: djs.InvalidCharacterError, Pos: 2}},
+		{`[1.]`, djs.InvalidJsonPtrError{Err: djs.InvalidCharacterError, Pos: 3}},
+		{`[tru]`, djs.InvalidJsonPtrError{Err: djs.InvalidCharacterError, Pos: 1}},
+		{`["a` + "\t" + `"]`, djs.InvalidJsonPtrError{Err: djs.InvalidCharacterError, Pos: 3}},
+		{`["\x"]`, djs.InvalidJsonPtrError{Err: djs.InvalidEscapeCharacterError, Pos: 3}},
+		{`["\u12G4"]`, djs.InvalidJsonPtrError{Err: djs.InvalidHexNumberError, Pos: 6}},
+		{`{"a":"b"]`, djs.InvalidJsonTokenPtrError{Pos: 8}},
+	} {
+		_, e := djs.JStructParseTape([]byte(el.in), nil)
+		s.Equal(el.err, e, el.in)
+	}
+}
+
+func (s *TapeTestSuite) TestTape_Index() {
+	n := 100000
+	in := "[" + strings.Repeat("1, ", n-1) + `{"a": 1, "b": [], "a": 3}]`
+	t, e := djs.JStructParseTape([]byte(in), nil)
+	s.NoError(e)
+	root := t.Root()
+	s.Equal(n, root.Size())
+	sum := int64(0)
+	for i := 0; i < n-1; i++ {
+		sum += root.GetIndex(i).Int()
+	}
+	s.Equal(int64(n-1), sum)
+	s.Nil(root.GetIndex(n))
+	s.Nil(root.GetIndex(-1))
+	obj := root.GetIndex(n - 1)
+	s.Equal(2, obj.Size())
+	s.Equal(0, obj.GetKey("b").Size())
+	// repeated keys resolved to the last one
+	s.Equal(int64(3), obj.GetKey("a").Int())
+	s.True(obj.HasKey("b"))
+	s.False(obj.HasKey("c"))
+	s.Nil(obj.GetKey("c"))
+	var keys []string
+	var values []int64
+	obj.(djs.IterOps).ForEachKey(func(k string, v djs.JStructOps) bool {
+		keys = append(keys, k)
+		values = append(values, v.Int())
+		return true
+	})
+	s.Equal([]string{"b", "a"}, keys)
+	s.Equal([]int64{0, 3}, values)
+	s.Equal(keys, obj.Keys())
+}
+
+func (s *TapeTestSuite) TestTape_RepeatedKeys() {
+	many := ""
+	for i := 0; i < 40; i++ {
+		many += fmt.Sprintf(`"k%d": %d, `, i%20, i)
+	}
+	for _, in := range []string{
+		`{"k2":"key","k2":"a"}`,
+		`{"a": 1, "b": {"x": 1, "x": [2], "y": 3}, "a": {"c": null}, "c": true}`,
+		"{" + many + `"k0": "last"}`,
+	} {
+		t, e := djs.JStructParseTape([]byte(in), nil)
+		s.NoError(e)
+		exp := &djs.JsonStruct{}
+		s.NoError(djs.JStructParseBytes([]byte(in), exp))
+		b, e := t.Root().MarshalJSON()
+		s.NoError(e)
+		act, e := exp.MarshalJSON()
+		s.NoError(e)
+		s.Equal(string(act), string(b), in)
+		s.Equal(exp.Size(), t.Root().Size(), in)
+		s.ElementsMatch(exp.Keys(), t.Root().Keys(), in)
+		b, e = djs.Canonicalize(t.Root())
+		s.NoError(e)
+		act, e = djs.Canonicalize(exp)
+		s.NoError(e)
+		s.Equal(string(act), string(b), in)
+	}
+}
+
+func (s *TapeTestSuite) TestTape_Options() {
+	data := []byte(`{"at": "2015-05-14T12:34:56Z", "arr": ["2015-05-14T12:34:56Z"]}`)
+	t, e := djs.JStructParseTapeWithOptions(data, nil, djs.ParseOptions{NoTimeDetection: true})
+	s.NoError(e)
+	s.True(t.Root().GetKey("at").IsString())
+	s.True(t.Root().GetKey("arr").GetIndex(0).IsString())
+	s.Equal("2015-05-14T12:34:56Z", t.Root().GetKey("at").String())
+	t, e = djs.JStructParseTapeWithOptions(data, t, djs.ParseOptions{})
+	s.NoError(e)
+	s.True(t.Root().GetKey("at").IsTime())
+	for _, opts := range []djs.ParseOptions{
+		{Relaxed: true},
+		{MaxDepth: 2},
+		{DuplicateKeys: djs.DuplicateKeysError},
+		{TimeLayouts: []string{djs.TimeLayoutDate}},
+		{TimeEpochKeys: map[string]djs.EpochUnit{"ts": djs.EpochSeconds}},
+		{BinaryPaths: []string{"/bin"}},
+	} {
+		_, e = djs.JStructParseTapeWithOptions(data, nil, opts)
+		s.Equal(djs.UnsupportedOptionError, e, "%+v", opts)
+	}
+}
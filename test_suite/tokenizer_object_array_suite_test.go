@@ -183,6 +183,78 @@ func (s *TokenizerTestObjArrSuite) TestTokenizer_Next_Array_Primitive() {
 	}
 }
 
+func (s *TokenizerTestObjArrSuite) TestTokenizer_Next_Object_Nested() {
+	tbl := []TokenizerTestObjArrElement{
+		{"obj:00", []byte(` {} `), []TokenizerTestExpectation{
+			{"00", djs.KindLiteral, djs.LevelObject, nil, nil},
+			{"01", djs.KindLiteral, djs.LevelObjectEnd, nil, nil},
+			{"02", djs.KindUnknown, djs.LevelRoot, nil, io.EOF},
+		}},
+		{"obj:01", []byte(` { "a" : 1 , "b":"x" } `), []TokenizerTestExpectation{
+			{"00", djs.KindLiteral, djs.LevelObject, nil, nil},
+			{"01", djs.KindString, djs.LevelKey, []byte(`"a"`), nil},
+			{"02", djs.KindNumber, djs.LevelValue, []byte(`1`), nil},
+			{"03", djs.KindString, djs.LevelKey, []byte(`"b"`), nil},
+			{"04", djs.KindString, djs.LevelValueLast, []byte(`"x"`), nil},
+			{"05", djs.KindUnknown, djs.LevelRoot, nil, io.EOF},
+		}},
+		{"obj:02", []byte(`{"a":{"b":[]},"c":[{}]}`), []TokenizerTestExpectation{
+			{"00", djs.KindLiteral, djs.LevelObject, nil, nil},
+			{"01", djs.KindString, djs.LevelKey, []byte(`"a"`), nil},
+			{"02", djs.KindLiteral, djs.LevelObject, nil, nil},
+			{"03", djs.KindString, djs.LevelKey, []byte(`"b"`), nil},
+			{"04", djs.KindLiteral, djs.LevelArray, nil, nil},
+			{"05", djs.KindLiteral, djs.LevelArrayEnd, nil, nil},
+			{"06", djs.KindLiteral, djs.LevelObjectEnd, nil, nil},
+			{"07", djs.KindString, djs.LevelKey, []byte(`"c"`), nil},
+			{"08", djs.KindLiteral, djs.LevelArray, nil, nil},
+			{"09", djs.KindLiteral, djs.LevelObject, nil, nil},
+			{"10", djs.KindLiteral, djs.LevelObjectEnd, nil, nil},
+			{"11", djs.KindLiteral, djs.LevelArrayEnd, nil, nil},
+			{"12", djs.KindLiteral, djs.LevelObjectEnd, nil, nil},
+			{"13", djs.KindUnknown, djs.LevelRoot, nil, io.EOF},
+		}},
+		// Invalid
+		{"obj:100", []byte(`{"a" 1}`), []TokenizerTestExpectation{
+			{"00", djs.KindLiteral, djs.LevelObject, nil, nil},
+			{"01", djs.KindUnknown, djs.LevelObject, []byte(`"a" 1`),
+				djs.InvalidJsonPtrError{Pos: 5}},
+		}},
+		{"obj:101", []byte(`{1:1}`), []TokenizerTestExpectation{
+			{"00", djs.KindLiteral, djs.LevelObject, nil, nil},
+			{"01", djs.KindUnknown, djs.LevelObject, []byte(`1`),
+				djs.InvalidJsonTokenPtrError{Pos: 1}},
+		}},
+		{"obj:102", []byte(`{"a":1]`), []TokenizerTestExpectation{
+			{"00", djs.KindLiteral, djs.LevelObject, nil, nil},
+			{"01", djs.KindString, djs.LevelKey, []byte(`"a"`), nil},
+			{"02", djs.KindUnknown, djs.LevelKey, []byte(`1]`),
+				djs.InvalidJsonPtrError{Pos: 6}},
+		}},
+	}
+	for _, el := range tbl {
+		RunTokenizerTestCaseAndExpectations(s, el)
+	}
+}
+
+func (s *TokenizerTestObjArrSuite) TestTokenizer_Reset() {
+	tk := djs.NewJStructTokenizer(djs.NewJStructScanner(bytes.NewBuffer([]byte(`{"a":[1,`))))
+	for i := 0; i < 4; i++ {
+		s.NoError(tk.Next())
+	}
+	s.Equal(djs.LevelValue, tk.Level())
+	tk.Reset(bytes.NewBuffer([]byte(` [true] `)))
+	s.Equal(djs.LevelRoot, tk.Level())
+	s.Equal(djs.KindUnknown, tk.Kind())
+	s.NoError(tk.Next())
+	s.Equal(djs.LevelArray, tk.Level())
+	s.NoError(tk.Next())
+	s.Equal(djs.KindTrue, tk.Kind())
+	s.Equal(djs.LevelValueLast, tk.Level())
+	s.Equal([]byte(`true`), tk.Value())
+	s.ErrorIs(tk.Next(), io.EOF)
+}
+
 func RunTokenizerTestCaseAndExpectations(s *TokenizerTestObjArrSuite, el TokenizerTestObjArrElement) {
 	s.T().Run(el.idx, func(t *testing.T) {
 		b := bytes.NewBuffer(el.in)
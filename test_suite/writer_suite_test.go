@@ -0,0 +1,320 @@
+package test_suite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	djs "github.com/Pencroff/JsonStruct"
+	tl "github.com/Pencroff/JsonStruct/tool"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJStruct_Writer(t *testing.T) {
+	s := new(WriterTestSuite)
+	suite.Run(t, s)
+}
+
+type WriterTestSuite struct {
+	suite.Suite
+}
+
+func (s *WriterTestSuite) TestWriter_Document() {
+	buf := bytes.Buffer{}
+	w := djs.NewJStructWriter(&buf)
+	s.NoError(w.BeginObject())
+	s.NoError(w.Key("a"))
+	s.NoError(w.BeginArray())
+	s.NoError(w.Int(-1))
+	s.NoError(w.Uint(math.MaxUint64))
+	s.NoError(w.Float(1.5e-7))
+	s.NoError(w.String("<\"q\">\n\u2028"))
+	s.NoError(w.Time(time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)))
+	s.NoError(w.Null())
+	s.NoError(w.Bool(true))
+	s.NoError(w.BeginArray())
+	s.NoError(w.End())
+	s.NoError(w.BeginObject())
+	s.NoError(w.End())
+	s.NoError(w.End())
+	s.NoError(w.Key("b"))
+	s.NoError(w.Bool(false))
+	s.NoError(w.End())
+	s.Equal(0, buf.Len())
+	s.NoError(w.Close())
+	exp := `{"a":[-1,18446744073709551615,1.5e-7,"\u003c\"q\"\u003e\n\u2028","2022-01-02T03:04:05Z",null,true,[],{}],"b":false}`
+	s.Equal(exp, buf.String())
+	s.True(json.Valid(buf.Bytes()))
+}
+
+func (s *WriterTestSuite) TestWriter_Value() {
+	js := JsonStructFactory()
+	s.NoError(djs.JStructParseBytes([]byte(`{"z": [1, 2.5, "x"], "a": {"b": null}}`), js))
+	buf := bytes.Buffer{}
+	w := djs.NewJStructWriter(&buf)
+	s.NoError(w.BeginArray())
+	s.NoError(w.Value(js))
+	s.NoError(w.Value(js.GetKey("z")))
+	s.NoError(w.Value(nil))
+	s.NoError(w.End())
+	s.NoError(w.Close())
+	s.Equal(`[{"a":{"b":null},"z":[1,2.5,"x"]},[1,2.5,"x"],null]`, buf.String())
+
+	b, e := json.Marshal(js)
+	s.NoError(e)
+	s.Equal(`{"a":{"b":null},"z":[1,2.5,"x"]}`, string(b))
+
+	w = djs.NewJStructWriter(&buf)
+	s.NoError(w.Int(1))
+	s.NoError(w.Close())
+	s.Equal(`1`, buf.String()[buf.Len()-1:])
+}
+
+func (s *WriterTestSuite) TestWriter_Stream() {
+	buf := bytes.Buffer{}
+	w := djs.NewJStructWriter(&buf)
+	s.NoError(w.BeginArray())
+	items := 100000
+	for i := 0; i < items; i++ {
+		s.NoError(w.BeginObject())
+		s.NoError(w.Key("id"))
+		s.NoError(w.Int(int64(i)))
+		s.NoError(w.End())
+		if i == 0 {
+			s.NoError(w.Flush())
+			s.Equal(`[{"id":0}`, buf.String())
+		}
+	}
+	s.NoError(w.End())
+	s.NoError(w.Close())
+	var res []struct{ Id int }
+	s.NoError(json.Unmarshal(buf.Bytes(), &res))
+	s.Equal(items, len(res))
+	s.Equal(items-1, res[items-1].Id)
+}
+
+func (s *WriterTestSuite) TestWriter_Context() {
+	data, e := tl.ReadGzip("../benchmark/data/canada.json.gz")
+	s.NoError(e)
+	js := JsonStructFactory()
+	s.NoError(djs.JStructParseBytes(data, js))
+	exp := bytes.Buffer{}
+	s.NoError(djs.JStructSerializeWithOptions(js, &exp, djs.SerializeOptions{Float: djs.FloatExponent}))
+	buf := bytes.Buffer{}
+	s.NoError(djs.JStructSerializeContext(context.Background(), js, &buf, djs.SerializeOptions{Float: djs.FloatExponent}))
+	s.Equal(exp.String(), buf.String())
+
+	buf.Reset()
+	e = djs.JStructSerializeContext(&cancelAfterContext{Context: context.Background(), n: 10000}, js, &buf, djs.SerializeOptions{})
+	s.ErrorIs(e, context.Canceled)
+	s.Equal(buf.Len(), e.(djs.ContextError).Pos)
+	s.Greater(buf.Len(), 0)
+	s.Less(buf.Len(), exp.Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf.Reset()
+	e = djs.JStructSerializeContext(ctx, js, &buf, djs.SerializeOptions{})
+	s.Equal(djs.ContextError{Err: context.Canceled, Pos: 0}, e)
+	s.Equal(0, buf.Len())
+
+	// source text of lazy tree kept
+	lz, e := djs.JStructParseLazy([]byte(`{"b": [1.0, {}], "a": 1}`), djs.ParseOptions{})
+	s.NoError(e)
+	lz.GetKey("a").SetInt(2)
+	s.NoError(djs.JStructSerializeContext(context.Background(), lz, &buf, djs.SerializeOptions{}))
+	s.Equal(`{"a":2,"b":[1.0, {}]}`, buf.String())
+}
+
+func (s *WriterTestSuite) TestWriter_Misuse() {
+	for idx, fn := range []func(w djs.JStructWriter) error{
+		func(w djs.JStructWriter) error { return w.Key("a") },
+		func(w djs.JStructWriter) error { return w.End() },
+		func(w djs.JStructWriter) error { return w.Close() },
+		func(w djs.JStructWriter) error { w.Null(); return w.Null() },
+		func(w djs.JStructWriter) error { w.BeginArray(); w.End(); return w.BeginArray() },
+		func(w djs.JStructWriter) error { w.BeginArray(); return w.Key("a") },
+		func(w djs.JStructWriter) error { w.BeginArray(); w.End(); return w.End() },
+		func(w djs.JStructWriter) error { w.BeginArray(); return w.Close() },
+		func(w djs.JStructWriter) error { w.BeginObject(); return w.Int(1) },
+		func(w djs.JStructWriter) error { w.BeginObject(); return w.BeginArray() },
+		func(w djs.JStructWriter) error { w.BeginObject(); w.Key("a"); return w.Key("b") },
+		func(w djs.JStructWriter) error { w.BeginObject(); w.Key("a"); return w.End() },
+	} {
+		buf := bytes.Buffer{}
+		w := djs.NewJStructWriter(&buf)
+		s.ErrorIs(fn(w), djs.WriterStateError, "case %d", idx)
+		// error is sticky
+		s.ErrorIs(w.Flush(), djs.WriterStateError, "case %d", idx)
+		s.ErrorIs(w.Bool(true), djs.WriterStateError, "case %d", idx)
+		s.Equal(0, buf.Len(), "case %d", idx)
+	}
+}
+
+func (s *WriterTestSuite) TestWriter_Escape() {
+	str := "<a href=\"x\">&amp;</a>\\/\n\b\f\x01\x7f é€😀\u2028\u2029 \xff\xe2\x82"
+	exp, e := json.Marshal(map[string]string{str: str})
+	s.NoError(e)
+	for _, el := range []struct {
+		opts djs.SerializeOptions
+		out  string
+	}{
+		{djs.SerializeOptions{}, string(exp)},
+		{djs.SerializeOptions{Escape: djs.EscapeHTML}, string(exp)},
+		{djs.SerializeOptions{Escape: djs.EscapeASCII},
+			`"<a href=\"x\">&amp;</a>\\/\n\b\f\u0001` + "\x7f" + ` \u00e9\u20ac\ud83d\ude00\u2028\u2029 \ufffd\ufffd\ufffd"`},
+		{djs.SerializeOptions{Escape: djs.EscapeRaw},
+			"\"<a href=\\\"x\\\">&amp;</a>\\\\/\\n\\b\\f\\u0001\x7f é€😀\u2028\u2029 \xff\xe2\x82\""},
+	} {
+		js := JsonStructFactory()
+		js.AsObject()
+		s.NoError(js.SetKey(str, str))
+		buf := bytes.Buffer{}
+		s.NoError(djs.JStructSerializeWithOptions(js, &buf, el.opts))
+		out := el.out
+		if out[0] == '"' {
+			out = "{" + out + ":" + out + "}"
+		}
+		s.Equal(out, buf.String(), "mode %d", el.opts.Escape)
+
+		buf.Reset()
+		w := djs.NewJStructWriterWithOptions(&buf, el.opts)
+		s.NoError(w.BeginObject())
+		s.NoError(w.Key(str))
+		s.NoError(w.String(str))
+		s.NoError(w.End())
+		s.NoError(w.Close())
+		s.Equal(out, buf.String(), "mode %d", el.opts.Escape)
+	}
+}
+
+func (s *WriterTestSuite) TestWriter_Float() {
+	values := []float64{0, -1.5, 1e21, 1.5e-7, 123456.789}
+	for _, el := range []struct {
+		opts djs.SerializeOptions
+		out  string
+	}{
+		{djs.SerializeOptions{}, `[0,-1.5,1e+21,1.5e-7,123456.789]`},
+		{djs.SerializeOptions{Float: djs.FloatFixed, FloatPrecision: 2},
+			`[0.00,-1.50,1` + strings.Repeat("0", 21) + `.00,0.00,123456.79]`},
+		{djs.SerializeOptions{Float: djs.FloatFixed}, `[0,-1.5,1` + strings.Repeat("0", 21) + `,0.00000015,123456.789]`},
+		{djs.SerializeOptions{Float: djs.FloatFixed, FloatPrecision: -1}, `[0,-1.5,1` + strings.Repeat("0", 21) + `,0.00000015,123456.789]`},
+		{djs.SerializeOptions{Float: djs.FloatFixed, FloatPrecisionSet: true}, `[0,-2,1` + strings.Repeat("0", 21) + `,0,123457]`},
+		{djs.SerializeOptions{Float: djs.FloatExponent, FloatPrecision: 3}, `[0.000e+00,-1.500e+00,1.000e+21,1.500e-07,1.235e+05]`},
+		{djs.SerializeOptions{Float: djs.FloatExponent}, `[0e+00,-1.5e+00,1e+21,1.5e-07,1.23456789e+05]`},
+		{djs.SerializeOptions{Float: djs.FloatExponent, FloatPrecisionSet: true}, `[0e+00,-2e+00,1e+21,1e-07,1e+05]`},
+	} {
+		js := JsonStructFactory()
+		js.AsArray()
+		for _, v := range values {
+			s.NoError(js.Push(v))
+		}
+		buf := bytes.Buffer{}
+		s.NoError(djs.JStructSerializeWithOptions(js, &buf, el.opts))
+		s.Equal(el.out, buf.String())
+		if el.opts == (djs.SerializeOptions{}) {
+			exp, e := json.Marshal(values)
+			s.NoError(e)
+			s.Equal(string(exp), buf.String())
+		}
+	}
+}
+
+func (s *WriterTestSuite) TestWriter_NonFinite() {
+	values := []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1}
+	for _, el := range []struct {
+		policy djs.NonFinitePolicy
+		out    string
+	}{
+		{djs.NonFiniteNull, `[null,null,null,1]`},
+		{djs.NonFiniteString, `["NaN","Infinity","-Infinity",1]`},
+		{djs.NonFiniteLiteral, `[NaN,Infinity,-Infinity,1]`},
+	} {
+		buf := bytes.Buffer{}
+		w := djs.NewJStructWriterWithOptions(&buf, djs.SerializeOptions{NonFinite: el.policy})
+		s.NoError(w.BeginArray())
+		for _, v := range values {
+			s.NoError(w.Float(v))
+		}
+		s.NoError(w.End())
+		s.NoError(w.Close())
+		s.Equal(el.out, buf.String())
+	}
+
+	js := JsonStructFactory()
+	js.AsObject()
+	s.NoError(js.SetKey("a", math.NaN()))
+	_, e := djs.MarshalJSON(js)
+	s.ErrorIs(e, djs.NonFiniteFloatError)
+	buf := bytes.Buffer{}
+	w := djs.NewJStructWriter(&buf)
+	s.ErrorIs(w.Float(math.Inf(1)), djs.NonFiniteFloatError)
+	s.ErrorIs(w.Close(), djs.NonFiniteFloatError)
+	s.Equal(0, buf.Len())
+}
+
+func (s *WriterTestSuite) TestWriter_Time() {
+	data := `{"a":"2015-05-14T12:34:56.100+02:00","b":"2015-05-14","c":1431599696789}`
+	js := JsonStructFactory()
+	s.NoError(djs.JStructParseBytesWithOptions([]byte(data), js, djs.ParseOptions{
+		TimeLayouts:   []string{djs.TimeLayoutDate},
+		TimeEpochKeys: map[string]djs.EpochUnit{"c": djs.EpochMillis},
+	}))
+	s.True(js.GetKey("c").IsTime())
+	b, e := djs.MarshalJSON(js)
+	s.NoError(e)
+	s.Equal(data, string(b))
+
+	s.NoError(js.SetKey("d", time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)))
+	buf := bytes.Buffer{}
+	s.NoError(djs.JStructSerialize(js, &buf))
+	s.Equal(data[:len(data)-1]+`,"d":"2022-01-02T03:04:05.000000006Z"}`, buf.String())
+
+	buf.Reset()
+	s.NoError(djs.JStructSerializeWithOptions(js, &buf, djs.SerializeOptions{TimeLocation: time.UTC}))
+	s.Equal(`{"a":"2015-05-14T10:34:56.1Z","b":"2015-05-14T00:00:00Z","c":"2015-05-14T10:34:56.789Z","d":"2022-01-02T03:04:05.000000006Z"}`, buf.String())
+
+	buf.Reset()
+	w := djs.NewJStructWriterWithOptions(&buf, djs.SerializeOptions{TimeLayout: "<2006/01/02>", TimeLocation: time.FixedZone("", -3600)})
+	s.NoError(w.BeginArray())
+	s.NoError(w.Value(js.GetKey("a")))
+	s.NoError(w.Time(time.Date(2022, 1, 2, 0, 4, 5, 0, time.UTC)))
+	s.NoError(w.End())
+	s.NoError(w.Close())
+	s.Equal(`["\u003c2015/05/14\u003e","\u003c2022/01/01\u003e"]`, buf.String())
+
+	tp, e := djs.JStructParseTape([]byte(data), nil)
+	s.NoError(e)
+	b, e = tp.Root().MarshalJSON()
+	s.NoError(e)
+	s.Equal(`{"a":"2015-05-14T12:34:56.100+02:00","b":"2015-05-14","c":1431599696789}`, string(b))
+	s.Equal("2015-05-14T12:34:56.100+02:00", tp.Root().GetKey("a").String())
+}
+
+func (s *WriterTestSuite) TestWriter_Binary() {
+	data := []byte{0, 1, 2, 0xfb, 0xff}
+	js := JsonStructFactory()
+	js.AsObject()
+	s.NoError(js.SetKey("a", data))
+	b, e := json.Marshal(js)
+	s.NoError(e)
+	exp, _ := json.Marshal(map[string][]byte{"a": data})
+	s.Equal(string(exp), string(b))
+
+	buf := bytes.Buffer{}
+	w := djs.NewJStructWriterWithOptions(&buf, djs.SerializeOptions{BinaryURL: true})
+	s.NoError(w.BeginArray())
+	s.NoError(w.Value(js))
+	s.NoError(w.Bytes(data))
+	s.NoError(w.Bytes(nil))
+	s.NoError(w.End())
+	s.NoError(w.Close())
+	s.Equal(`[{"a":"AAEC-_8="},"AAEC-_8=",""]`, buf.String())
+
+	b, e = djs.Canonicalize(js)
+	s.NoError(e)
+	s.Equal(`{"a":"AAEC+/8="}`, string(b))
+}
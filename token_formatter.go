@@ -0,0 +1,154 @@
+package JsonStruct
+
+import (
+	"bufio"
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+)
+
+// Compact writes JSON of src to dst without insignificant whitespaces.
+// Tokens are copied as is, data streamed without building of tree.
+// Output is written while src is read: on error of src dst keeps the part formatted before it,
+// the first write error of dst stops reading of src and returned as is.
+func Compact(dst io.Writer, src io.Reader) error {
+	return formatTokens(dst, src, tokenFormatter{})
+}
+
+// Indent writes JSON of src to dst with every element of object or array on new line,
+// started by prefix and copy of indent per nesting level. Empty containers stay on the same line.
+// Same as encoding/json Indent leading whitespaces of src are dropped, trailing ones are copied to dst.
+// Tokens are copied as is, data streamed without building of tree.
+// Output is written while src is read: on error of src dst keeps the part formatted before it,
+// the first write error of dst stops reading of src and returned as is.
+func Indent(dst io.Writer, src io.Reader, prefix, indent string) error {
+	return formatTokens(dst, src, tokenFormatter{prefix: prefix, indent: indent, pretty: true})
+}
+
+// formatTokens writes tokens of src to dst, stops at the first error of src or dst
+func formatTokens(dst io.Writer, src io.Reader, f tokenFormatter) error {
+	tc := acquireTokenizer(src, ParseOptions{})
+	defer releaseTokenizer(tc)
+	out := &formatterWriter{wr: dst}
+	f.w = bufio.NewWriter(out)
+	done := false
+	for {
+		e := tc.Next()
+		if e == io.EOF && done {
+			if f.pretty {
+				f.w.Write(tc.tail)
+			}
+			return f.w.Flush()
+		}
+		if e != nil {
+			f.w.Flush()
+			return e
+		}
+		f.token(tc.Level(), tc.Value())
+		if out.err != nil {
+			return out.err
+		}
+		done = len(f.stack) == 0
+	}
+}
+
+// formatterWriter keeps the first error of wr, buffered writer reports it only on the next flush
+type formatterWriter struct {
+	wr  io.Writer
+	err error
+}
+
+func (w *formatterWriter) Write(p []byte) (int, error) {
+	n, e := w.wr.Write(p)
+	if e == nil && n < len(p) {
+		e = io.ErrShortWrite
+	}
+	if e != nil && w.err == nil {
+		w.err = e
+	}
+	return n, e
+}
+
+// tokenFormatter writes tokens restoring delimiters consumed by tokenizer
+type tokenFormatter struct {
+	w      *bufio.Writer
+	prefix string
+	indent string
+	pretty bool
+	stack  []formatterContainer // open containers
+	key    bool                 // value of key expected
+}
+
+type formatterContainer struct {
+	end byte // closing bracket
+	n   int  // number of items
+}
+
+func (f *tokenFormatter) token(level TokenizerLevel, v []byte) {
+	switch level {
+	case LevelKey:
+		f.item()
+		f.w.Write(v)
+		f.w.WriteByte(h.ColonCh)
+		if f.pretty {
+			f.w.WriteByte(' ')
+		}
+		f.key = true
+		return
+	case LevelArrayEnd, LevelObjectEnd:
+		f.close()
+		return
+	}
+	if !f.key {
+		f.item()
+	}
+	f.key = false
+	switch level {
+	case LevelArray:
+		f.stack = append(f.stack, formatterContainer{end: h.CloseBracketCh})
+		f.w.WriteByte(h.OpenBracketCh)
+	case LevelObject:
+		f.stack = append(f.stack, formatterContainer{end: h.CloseBraceCh})
+		f.w.WriteByte(h.OpenBraceCh)
+	default:
+		f.w.Write(v)
+		if level == LevelValueLast {
+			// closing bracket consumed as delimiter of the last value
+			f.close()
+		}
+	}
+}
+
+// item starts the next item of current container
+func (f *tokenFormatter) item() {
+	l := len(f.stack)
+	if l == 0 {
+		return
+	}
+	c := &f.stack[l-1]
+	if c.n > 0 {
+		f.w.WriteByte(h.CommaCh)
+	}
+	c.n++
+	f.newLine(l)
+}
+
+func (f *tokenFormatter) close() {
+	l := len(f.stack) - 1
+	c := f.stack[l]
+	f.stack = f.stack[:l]
+	if c.n > 0 {
+		f.newLine(l)
+	}
+	f.w.WriteByte(c.end)
+}
+
+func (f *tokenFormatter) newLine(depth int) {
+	if !f.pretty {
+		return
+	}
+	f.w.WriteByte(h.NewLineCh)
+	f.w.WriteString(f.prefix)
+	for i := 0; i < depth; i++ {
+		f.w.WriteString(f.indent)
+	}
+}
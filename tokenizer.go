@@ -84,10 +84,18 @@ type JStructTokenizer interface {
 	Value() []byte
 	Kind() TokenizerKind
 	Level() TokenizerLevel
+	Reset(rd io.Reader) // start tokenizing of new data, scanner reused
 }
 
 func NewJStructTokenizer(sc JStructScanner) JStructTokenizer {
-	return &JStructTokenizerImpl{sc: sc, scLevel: LevelRoot, depth: []TokenizerLevel{LevelRoot}}
+	return NewJStructTokenizerWithOptions(sc, ParseOptions{})
+}
+
+// NewJStructTokenizerWithOptions creates tokenizer validating limits of opts
+func NewJStructTokenizerWithOptions(sc JStructScanner, opts ParseOptions) JStructTokenizer {
+	t := &JStructTokenizerImpl{sc: sc, scLevel: LevelRoot, depth: []TokenizerLevel{LevelRoot}, count: []int{0}}
+	t.SetOptions(opts)
+	return t
 }
 
 type JStructTokenizerImpl struct {
@@ -95,12 +103,115 @@ type JStructTokenizerImpl struct {
 	scType  TokenizerKind
 	scLevel TokenizerLevel
 	v       []byte
-	depth   []TokenizerLevel
+	depth   []TokenizerLevel // stack of containers, LevelRoot at the bottom
+	count   []int            // number of items in containers of depth
+	closed  bool             // nested container closed, parent expects delimiter
+	done    bool             // root value read, only whitespaces allowed
+	tail    []byte           // whitespaces after root value, set at the end of data
+	//=======
+	opts    ParseOptions
+	limited bool  // any of limits set or lines tracked, next validates each byte
+	first   int   // index of first byte of string or number token, -1 outside of them
+	start   int   // index of first byte of current token
+	err     error // limit error, replaces error of the current token
+	// lines tracked for ParseOptions.Spans
+	spans     bool
+	lines     []int // starts of lines after the last position
+	line      int   // 0-based line of the last position
+	lineStart int   // start of the line
+}
+
+// SetOptions sets limits validated by tokenizer
+func (t *JStructTokenizerImpl) SetOptions(opts ParseOptions) {
+	t.opts = opts
+	t.spans = opts.Spans != nil
+	t.limited = opts.limited() || t.spans
+}
+
+// Reset resets scanner with rd and drops tokenizer state
+func (t *JStructTokenizerImpl) Reset(rd io.Reader) {
+	t.sc.Reset(rd)
+	t.scType = KindUnknown
+	t.scLevel = LevelRoot
+	t.v = nil
+	t.depth = append(t.depth[:0], LevelRoot)
+	t.count = append(t.count[:0], 0)
+	t.closed = false
+	t.done = false
+	t.tail = nil
+	t.err = nil
+	t.lines = t.lines[:0]
+	t.line = 0
+	t.lineStart = 0
+}
+
+// next moves scanner to the next byte and validates limits
+func (t *JStructTokenizerImpl) next() error {
+	e := t.sc.Next()
+	if e != nil || !t.limited {
+		return e
+	}
+	return t.checkLimits()
+}
+
+// checkLimits validates document size and length of string or number token, tracks lines
+func (t *JStructTokenizerImpl) checkLimits() error {
+	idx := t.sc.Index()
+	if t.spans && t.sc.Current() == h.NewLineCh {
+		t.lines = append(t.lines, idx+1)
+	}
+	o := &t.opts
+	switch {
+	case o.MaxBytes > 0 && idx >= o.MaxBytes:
+		return t.limitExceeded(MaxBytesExceededError)
+	case t.first < 0:
+		return nil
+	}
+	l := idx - t.first + 1
+	switch t.scType {
+	case KindString:
+		// quotes excluded
+		if o.MaxStringLength > 0 && l > o.MaxStringLength+2 {
+			return t.limitExceeded(MaxStringLengthExceededError)
+		}
+	case KindNumber, KindFloatNumber:
+		// byte after number is read to find the end of it
+		if o.MaxNumberLength > 0 && l > o.MaxNumberLength && h.NumberCh[t.sc.Current()] {
+			return t.limitExceeded(MaxNumberLengthExceededError)
+		}
+	}
+	return nil
+}
+
+// countItem counts key or value in current container
+func (t *JStructTokenizerImpl) countItem() error {
+	last := len(t.count) - 1
+	t.count[last]++
+	if !t.limited {
+		return nil
+	}
+	switch t.container() {
+	case LevelArray:
+		if t.opts.MaxArrayLength > 0 && t.count[last] > t.opts.MaxArrayLength {
+			return t.limitExceeded(MaxArrayLengthExceededError)
+		}
+	case LevelObject:
+		if t.opts.MaxKeysPerObject > 0 && t.count[last] > t.opts.MaxKeysPerObject {
+			return t.limitExceeded(MaxKeysPerObjectExceededError)
+		}
+	}
+	return nil
+}
+
+// limitExceeded keeps limit error, tokenizer returns it instead of error caused by interrupted scanning
+func (t *JStructTokenizerImpl) limitExceeded(e error) error {
+	t.err = LimitExceededError{Err: e, Pos: t.sc.Index()}
+	return t.err
 }
 
 func (t *JStructTokenizerImpl) nextSkipWhiteSpace() error {
 	for {
-		err := t.sc.Next()
+		err := t.next()
 		if err != nil {
 			return err
 		}
@@ -109,13 +220,21 @@ func (t *JStructTokenizerImpl) nextSkipWhiteSpace() error {
 			t.sc.Bytes()
 			continue
 		}
+		if b == h.SlashCh && t.opts.Relaxed {
+			err = t.skipComment()
+			if err != nil {
+				return err
+			}
+			t.sc.Bytes()
+			continue
+		}
 		return nil
 	}
 }
 
 func (t *JStructTokenizerImpl) nextKeepWhiteSpace() error {
 	for {
-		err := t.sc.Next()
+		err := t.next()
 		if err != nil {
 			return err
 		}
@@ -123,15 +242,58 @@ func (t *JStructTokenizerImpl) nextKeepWhiteSpace() error {
 		if h.SpaceCh[ch] {
 			continue
 		}
+		if ch == h.SlashCh && t.opts.Relaxed {
+			err = t.skipComment()
+			if err != nil {
+				return err
+			}
+			continue
+		}
 		return nil
 	}
 }
 
+// keepWhiteSpaceFrom skips whitespaces (and comments in relaxed mode) starting from current byte ch,
+// returns the first byte after them
+func (t *JStructTokenizerImpl) keepWhiteSpaceFrom(ch byte) (byte, error) {
+	if ch == h.SlashCh && t.opts.Relaxed {
+		e := t.skipComment()
+		if e != nil {
+			return t.sc.Current(), e
+		}
+	} else if !h.SpaceCh[ch] {
+		return ch, nil
+	}
+	e := t.nextKeepWhiteSpace()
+	return t.sc.Current(), e
+}
+
 func (t *JStructTokenizerImpl) Next() error {
+	if t.err != nil {
+		return t.err
+	}
+	e := t.readToken()
+	if t.err != nil {
+		t.scType = KindUnknown
+		return t.err
+	}
+	return e
+}
+
+// readToken reads the next token
+func (t *JStructTokenizerImpl) readToken() error {
+	t.first = -1
+	if t.done {
+		return t.readEnd()
+	}
 	t.scType = KindUnknown
-	//t.scLevel = LevelUnknown
-	if t.scLevel == LevelValueLast || t.scLevel == LevelArrayEnd {
+	if t.scLevel == LevelValueLast || t.scLevel == LevelArrayEnd || t.scLevel == LevelObjectEnd {
 		t.PopLevel()
+		if t.scLevel == LevelRoot {
+			t.done = true
+			return t.readEnd()
+		}
+		t.closed = true
 	}
 	for {
 		err := t.nextSkipWhiteSpace()
@@ -142,46 +304,56 @@ func (t *JStructTokenizerImpl) Next() error {
 			}
 			return InvalidJsonError{Err: err}
 		}
-		switch t.sc.Current() {
-		//case '{':
-		//	t.scType = TokenObject
-		//	return nil
-		case '[':
-			t.PushLevel(LevelArray)
-			t.scType = KindLiteral
-			t.sc.Bytes()
-			t.v = nil
-			return nil
-		case 'n':
-			return t.ReadNull()
-		case 'f':
-			return t.ReadFalse()
-		case 't':
-			return t.ReadTrue()
-		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-			return t.ReadNumber(false)
-		case h.MinusCh:
-			return t.ReadNumber(true)
-		case h.QuoteCh:
-			return t.ReadStringTime()
-		//case ':':
-		//	t.scType = TokenKey
-		//	return nil
-		//case ',':
-		//	t.scType = TokenValue
-		//	return nil
-		case ']':
-			t.PopLevel()
-			t.scLevel = LevelArrayEnd
-			t.scType = KindLiteral
-			return nil
-		//case '}':
-		//	t.scType = TokenValueLast
-		//	return nil
-		default:
-			return InvalidJsonError{Err: err}
+		ch := t.sc.Current()
+		t.start = t.sc.Index()
+		if t.closed {
+			t.closed = false
+			if ch == h.CommaCh {
+				t.sc.Bytes()
+				t.scLevel = LevelValue
+				continue
+			}
+			return t.readContainerEnd(ch)
+		}
+		if t.container() == LevelObject && (t.scLevel == LevelObject || t.scLevel == LevelValue) {
+			if ch == h.QuoteCh {
+				if err = t.countItem(); err != nil {
+					return err
+				}
+			}
+			return t.readObjectKey(ch)
 		}
+		if t.container() == LevelArray && ch != h.CloseBracketCh {
+			if err = t.countItem(); err != nil {
+				return err
+			}
+		}
+		err = t.readValue(ch)
+		if err == nil && t.scLevel == LevelRoot {
+			t.done = true
+		}
+		return err
+	}
+}
+
+// Start returns position of the first byte of current token
+func (t *JStructTokenizerImpl) Start() int {
+	return t.start
+}
+
+// position resolves line and column of offset, lines tracked if ParseOptions.Spans set.
+// Offset should not be less than offset of the previous call.
+func (t *JStructTokenizerImpl) position(offset int) Position {
+	n := 0
+	for n < len(t.lines) && t.lines[n] <= offset {
+		t.lineStart = t.lines[n]
+		n++
+	}
+	if n > 0 {
+		t.line += n
+		t.lines = append(t.lines[:0], t.lines[n:]...)
 	}
+	return Position{Offset: offset, Line: t.line + 1, Column: offset - t.lineStart + 1}
 }
 
 func (t *JStructTokenizerImpl) Value() []byte {
@@ -196,23 +368,177 @@ func (t *JStructTokenizerImpl) Level() TokenizerLevel {
 	return t.scLevel
 }
 
-// PushLevel pushes the current level to the stack and sets the new level to the given value
+// PushLevel opens container with the given level (LevelArray or LevelObject)
 func (t *JStructTokenizerImpl) PushLevel(l TokenizerLevel) {
-	t.depth = append(t.depth, t.scLevel)
+	t.depth = append(t.depth, l)
+	t.count = append(t.count, 0)
 	t.scLevel = l
 }
 
-// PopLevel pops the current level from the stack and sets the new level to the popped value
+// PopLevel closes current container and sets the level of parent container
 func (t *JStructTokenizerImpl) PopLevel() {
 	last := len(t.depth) - 1
 	if last > 0 {
 		t.depth = t.depth[:last]
+		t.count = t.count[:last]
 		last -= 1
-
 	}
 	t.scLevel = t.depth[last]
 }
 
+// pushContainer opens container if depth limit allows
+func (t *JStructTokenizerImpl) pushContainer(l TokenizerLevel) error {
+	if t.opts.MaxDepth > 0 && len(t.depth) > t.opts.MaxDepth {
+		return t.limitExceeded(MaxDepthExceededError)
+	}
+	t.PushLevel(l)
+	t.scType = KindLiteral
+	t.sc.Bytes()
+	t.v = nil
+	return nil
+}
+
+// container returns level of current container
+func (t *JStructTokenizerImpl) container() TokenizerLevel {
+	return t.depth[len(t.depth)-1]
+}
+
+// readValue reads value started by ch
+func (t *JStructTokenizerImpl) readValue(ch byte) error {
+	switch ch {
+	case h.OpenBracketCh:
+		return t.pushContainer(LevelArray)
+	case h.OpenBraceCh:
+		return t.pushContainer(LevelObject)
+	case 'n':
+		return t.ReadNull()
+	case 'f':
+		return t.ReadFalse()
+	case 't':
+		return t.ReadTrue()
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return t.ReadNumber(false)
+	case h.MinusCh:
+		return t.ReadNumber(true)
+	case h.QuoteCh:
+		return t.ReadStringTime()
+	case h.CloseBracketCh:
+		// empty array or trailing comma in relaxed mode
+		if t.scLevel == LevelArray || t.opts.Relaxed && t.scLevel == LevelValue {
+			return t.readContainerEnd(ch)
+		}
+	default:
+		if t.opts.Relaxed {
+			return t.readRelaxedValue(ch)
+		}
+	}
+	return t.unexpectedToken()
+}
+
+// readObjectKey reads object key or end of empty object
+func (t *JStructTokenizerImpl) readObjectKey(ch byte) error {
+	if ch == h.QuoteCh {
+		return t.ReadKey()
+	}
+	// empty object or trailing comma in relaxed mode
+	if ch == h.CloseBraceCh && (t.scLevel == LevelObject || t.opts.Relaxed) {
+		return t.readContainerEnd(ch)
+	}
+	if t.opts.Relaxed {
+		return t.readRelaxedKey(ch)
+	}
+	return t.unexpectedToken()
+}
+
+// readContainerEnd reads closing bracket of current container
+func (t *JStructTokenizerImpl) readContainerEnd(ch byte) error {
+	switch {
+	case ch == h.CloseBracketCh && t.container() == LevelArray:
+		t.scLevel = LevelArrayEnd
+	case ch == h.CloseBraceCh && t.container() == LevelObject:
+		t.scLevel = LevelObjectEnd
+	default:
+		return t.unexpectedToken()
+	}
+	t.scType = KindLiteral
+	t.sc.Bytes()
+	t.v = nil
+	return nil
+}
+
+// readEnd checks that only whitespaces left after root value
+func (t *JStructTokenizerImpl) readEnd() error {
+	t.scType = KindUnknown
+	e := t.nextKeepWhiteSpace()
+	if e == io.EOF {
+		if b := t.sc.Bytes(); len(b) > 0 {
+			t.tail = b
+		}
+		t.v = nil
+		return io.EOF
+	}
+	t.v = t.sc.Bytes()
+	if e != nil {
+		return InvalidJsonPtrError{Pos: t.sc.Index(), Err: e}
+	}
+	return InvalidJsonTokenPtrError{Pos: t.sc.Index()}
+}
+
+func (t *JStructTokenizerImpl) unexpectedToken() error {
+	t.scType = KindUnknown
+	if t.container() == LevelRoot {
+		return InvalidJsonError{}
+	}
+	t.v = t.sc.Bytes()
+	return InvalidJsonTokenPtrError{Pos: t.sc.Index()}
+}
+
+// readValueEnd resolves the end of value with length l, ch is the first byte after value.
+// Root value should be followed by whitespaces only, value in container by delimiter.
+func (t *JStructTokenizerImpl) readValueEnd(ch byte, l int, e error) error {
+	if e == nil {
+		ch, e = t.keepWhiteSpaceFrom(ch)
+	}
+	if t.container() == LevelRoot {
+		if e == io.EOF {
+			b := t.sc.Bytes()
+			t.v, t.tail = b[:l], b[l:]
+			return nil
+		}
+	} else if e == nil && t.valueDelimiter(ch) {
+		t.v = t.sc.Bytes()[:l]
+		return nil
+	}
+	return t.invalidValue(e)
+}
+
+// valueDelimiter updates level by delimiter after value in container
+func (t *JStructTokenizerImpl) valueDelimiter(ch byte) bool {
+	switch ch {
+	case h.CommaCh:
+		t.scLevel = LevelValue
+	case h.CloseBracketCh:
+		if t.container() != LevelArray {
+			return false
+		}
+		t.scLevel = LevelValueLast
+	case h.CloseBraceCh:
+		if t.container() != LevelObject {
+			return false
+		}
+		t.scLevel = LevelValueLast
+	default:
+		return false
+	}
+	return true
+}
+
+func (t *JStructTokenizerImpl) invalidValue(e error) error {
+	t.scType = KindUnknown
+	t.v = t.sc.Bytes()
+	return InvalidJsonPtrError{Pos: t.sc.Index(), Err: e}
+}
+
 func (t *JStructTokenizerImpl) ReadNull() error {
 	return t.hardcodedToken(KindNull, h.NullTokenData)
 }
@@ -229,26 +555,47 @@ func (t *JStructTokenizerImpl) ReadNumber(hasMinus bool) error {
 	var idx, l int
 	t.scType = KindNumber
 	firstIdx := t.sc.Index()
-	e := t.sc.Next()
+	t.first = firstIdx
+	first := t.sc.Current()
+	e := t.next()
 	ch := t.sc.Current()
 	hasIntPart := !hasMinus || h.NumCh[ch]
+	// leading zeros are not allowed
+	zeroLead := !hasMinus && first == '0' || hasMinus && ch == '0'
+	if hasMinus && e == nil && t.opts.Relaxed && (ch == 'I' || ch == 'N') {
+		return t.readSignedLiteral(ch)
+	}
 	if e != nil || !h.NumCh[ch] {
 		goto afterLoop
 	}
+	if zeroLead && !hasMinus {
+		goto errLbl
+	}
 	// integer part
 	for {
-		e = t.sc.Next()
+		e = t.next()
 		ch = t.sc.Current()
 		if !h.NumCh[ch] || e != nil {
 			break
 		}
+		if zeroLead {
+			goto errLbl
+		}
 	}
 afterLoop:
 	idx = t.sc.Index()
 	l = idx - firstIdx
 	if e == io.EOF && h.NumCh[ch] {
-		t.v = t.sc.Bytes()
-		return nil
+		return t.readValueEnd(ch, l+1, e)
+	}
+	if e == nil && t.opts.Relaxed {
+		// `0x` or signed `-0x`
+		if zeroLead && (ch == 'x' || ch == 'X') && (l == 1 && !hasMinus || l == 2 && hasMinus) {
+			return t.readHexNumber(firstIdx)
+		}
+		if !hasIntPart && ch == h.PointCh {
+			return t.readFraction(firstIdx, false)
+		}
 	}
 	if !hasIntPart || e != nil {
 		goto errLbl
@@ -259,46 +606,27 @@ afterLoop:
 	if ch == h.ExpSmCh || ch == h.ExpCh {
 		return t.ReadExponentPart(firstIdx)
 	}
-	if t.scLevel == LevelRoot && !h.SpaceCh[ch] {
-		goto errLbl
-	}
-	if h.SpaceCh[ch] {
-		e = t.nextKeepWhiteSpace()
-		ch = t.sc.Current()
-		if h.SpaceCh[ch] {
-			t.v = t.sc.Bytes()[:l]
-			return nil
-		}
-	}
-	if t.scLevel != LevelRoot {
-		if ch == h.CommaCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValue
-			return nil
-		}
-		if ch == h.CloseBracketCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValueLast
-			return nil
-		}
-	}
+	return t.readValueEnd(ch, l, e)
 errLbl:
-	t.scType = KindUnknown
-	t.v = t.sc.Bytes()
-	return InvalidJsonPtrError{Pos: t.sc.Index(), Err: e}
+	return t.invalidValue(e)
 }
 
 func (t *JStructTokenizerImpl) ReadFractionPart(firstIdx int) error {
+	return t.readFraction(firstIdx, true)
+}
+
+// readFraction reads fraction part, intPart reports digits before point
+func (t *JStructTokenizerImpl) readFraction(firstIdx int, intPart bool) error {
 	var idx, l int
 	t.scType = KindFloatNumber
-	e := t.sc.Next()
+	e := t.next()
 	ch := t.sc.Current()
 	hasFractionPart := h.NumCh[ch]
 	if e != nil || !h.NumCh[ch] {
 		goto afterLoop
 	}
 	for {
-		e = t.sc.Next()
+		e = t.next()
 		ch = t.sc.Current()
 		if !h.NumCh[ch] || e != nil {
 			break
@@ -308,8 +636,14 @@ afterLoop:
 	idx = t.sc.Index()
 	l = idx - firstIdx
 	if e == io.EOF && h.NumCh[ch] {
-		t.v = t.sc.Bytes()
-		return nil
+		return t.readValueEnd(ch, l+1, e)
+	}
+	if !hasFractionPart && intPart && t.opts.Relaxed {
+		// trailing point, like `5.`
+		if e == io.EOF {
+			return t.readValueEnd(ch, l+1, e)
+		}
+		hasFractionPart = e == nil
 	}
 	if !hasFractionPart || e != nil {
 		goto errLbl
@@ -317,44 +651,19 @@ afterLoop:
 	if ch == h.ExpSmCh || ch == h.ExpCh {
 		return t.ReadExponentPart(firstIdx)
 	}
-	if t.scLevel == LevelRoot && !h.SpaceCh[ch] {
-		goto errLbl
-	}
-	if h.SpaceCh[ch] {
-		e = t.nextKeepWhiteSpace()
-		ch = t.sc.Current()
-		if h.SpaceCh[ch] {
-			t.v = t.sc.Bytes()[:l]
-			return nil
-		}
-	}
-	if t.scLevel != LevelRoot {
-		if ch == h.CommaCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValue
-			return nil
-		}
-		if ch == h.CloseBracketCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValueLast
-			return nil
-		}
-	}
+	return t.readValueEnd(ch, l, e)
 errLbl:
-	t.scType = KindUnknown
-	t.v = t.sc.Bytes()
-
-	return InvalidJsonPtrError{Pos: t.sc.Index(), Err: e}
+	return t.invalidValue(e)
 }
 
 func (t *JStructTokenizerImpl) ReadExponentPart(firstIdx int) error {
 	var idx, l int
 	t.scType = KindFloatNumber
 	hasExpNumPart := false
-	e := t.sc.Next()
+	e := t.next()
 	ch := t.sc.Current()
 	if ch == '+' || ch == '-' {
-		e = t.sc.Next()
+		e = t.next()
 		ch = t.sc.Current()
 	}
 	hasExpNumPart = h.NumCh[ch]
@@ -363,7 +672,7 @@ func (t *JStructTokenizerImpl) ReadExponentPart(firstIdx int) error {
 	}
 
 	for {
-		e = t.sc.Next()
+		e = t.next()
 		ch = t.sc.Current()
 		if !h.NumCh[ch] || e != nil {
 			break
@@ -373,49 +682,14 @@ afterLoop:
 	idx = t.sc.Index()
 	l = idx - firstIdx
 	if e == io.EOF && h.NumCh[ch] {
-		t.v = t.sc.Bytes()
-		return nil
+		return t.readValueEnd(ch, l+1, e)
 	}
 	if !hasExpNumPart || e != nil {
 		goto errLbl
 	}
-	if t.scLevel == LevelRoot && !h.SpaceCh[ch] {
-		goto errLbl
-	}
-	if h.SpaceCh[ch] {
-		e = t.nextKeepWhiteSpace()
-		ch = t.sc.Current()
-		if h.SpaceCh[ch] {
-			t.v = t.sc.Bytes()[:l]
-			return nil
-		}
-	}
-	if t.scLevel != LevelRoot {
-		if ch == h.CommaCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValue
-			return nil
-		}
-		if ch == h.CloseBracketCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValueLast
-			return nil
-		}
-	}
-	//if e == nil && h.SpaceCh[ch] {
-	//	idx := t.sc.Index()
-	//	e = t.nextKeepWhiteSpace()
-	//	ch = t.sc.Current()
-	//	if h.SpaceCh[ch] {
-	//		l := idx - first
-	//		t.v = t.sc.Bytes()[:l]
-	//		return nil
-	//	}
-	//}
+	return t.readValueEnd(ch, l, e)
 errLbl:
-	t.scType = KindUnknown
-	t.v = t.sc.Bytes()
-	return InvalidJsonPtrError{Pos: t.sc.Index(), Err: e}
+	return t.invalidValue(e)
 }
 
 // Read json string or time in RFC3339 format
@@ -424,120 +698,122 @@ func (t *JStructTokenizerImpl) ReadStringTime() error {
 	if err != nil {
 		return err
 	}
-	if h.IsTimeFormat(t.v) {
+	if !t.opts.NoTimeDetection && h.IsTimeFormat(t.v) {
 		t.scType = KindTime
 	}
 	return nil
 }
 
 func (t *JStructTokenizerImpl) ReadString() error {
+	return t.readString(h.QuoteCh)
+}
+
+// readString reads string enclosed by quote
+func (t *JStructTokenizerImpl) readString(quote byte) error {
 	t.scType = KindString
 	first := t.sc.Index()
-	var e error
-	var ch, prev byte
+	t.first = first
+	e := t.readStringBody(quote)
+	t.first = -1
+	if e != nil {
+		return t.invalidValue(e)
+	}
+	l := t.sc.Index() - first + 1
+	e = t.nextKeepWhiteSpace()
+	return t.readValueEnd(t.sc.Current(), l, e)
+}
+
+// ReadKey reads object key followed by colon
+func (t *JStructTokenizerImpl) ReadKey() error {
+	return t.readKey(h.QuoteCh)
+}
+
+// readKey reads key enclosed by quote
+func (t *JStructTokenizerImpl) readKey(quote byte) error {
+	t.scType = KindString
+	first := t.sc.Index()
+	t.first = first
+	e := t.readStringBody(quote)
+	t.first = -1
+	if e != nil {
+		return t.invalidValue(e)
+	}
+	l := t.sc.Index() - first + 1
+	e = t.nextKeepWhiteSpace()
+	if e != nil || t.sc.Current() != h.ColonCh {
+		return t.invalidValue(e)
+	}
+	t.v = t.sc.Bytes()[:l]
+	t.scLevel = LevelKey
+	return nil
+}
+
+// readStringBody reads string till closing quote, current byte is opening quote
+func (t *JStructTokenizerImpl) readStringBody(quote byte) error {
 	var escaped bool
-	var l, idx int
 	for {
-		e = t.sc.Next()
-		ch = t.sc.Current()
-		if ch == h.QuoteCh && prev != h.BackSlashCh ||
-			e != nil {
-			break
+		e := t.next()
+		if e != nil {
+			return e
 		}
-		if prev == h.BackSlashCh {
+		ch := t.sc.Current()
+		if escaped {
 			switch ch {
 			case 'u':
 				e = t.readHex(4)
 				if e != nil {
-					goto errLbl
+					return e
 				}
-			case 0x22, 0x2F, 0x5c, 'b', 'f', 'n', 'r', 't':
-				escaped = true
-				break
+			case h.QuoteCh, '/', h.BackSlashCh, 'b', 'f', 'n', 'r', 't':
 			default:
-				if !escaped {
-					e = InvalidEscapeCharacterError
-					goto errLbl
+				if !t.opts.Relaxed {
+					return InvalidEscapeCharacterError
+				}
+				e = t.readRelaxedEscape(ch)
+				if e != nil {
+					return e
 				}
-				escaped = false
 			}
+			escaped = false
+			continue
 		}
-		if ch == h.TabCh || ch == h.NewLineCh || ch == h.CarriageReturnCh {
-			e = InvalidCharacterError
-			goto errLbl
-		}
-		prev = ch
-	}
-	idx = t.sc.Index()
-	l = idx - first + 1
-	e = t.nextKeepWhiteSpace()
-	ch = t.sc.Current()
-	if idx == t.sc.Index() && ch == h.QuoteCh ||
-		h.SpaceCh[ch] {
-		t.v = t.sc.Bytes()[:l]
-		return nil
-	}
-	if t.scLevel != LevelRoot {
-		if ch == h.CommaCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValue
-			return nil
-		}
-		if ch == h.CloseBracketCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValueLast
+		switch {
+		case ch == quote:
 			return nil
+		case ch == h.BackSlashCh:
+			escaped = true
+		case ch < 0x20:
+			return InvalidCharacterError
 		}
 	}
-errLbl:
-	t.scType = KindUnknown
-	t.v = t.sc.Bytes()
-	return InvalidJsonPtrError{Pos: t.sc.Index(), Err: e}
 }
 
 func (t *JStructTokenizerImpl) hardcodedToken(
 	kind TokenizerKind, origin []byte) error {
+	return t.literalToken(kind, origin, len(origin))
+}
+
+// literalToken reads origin as token of length l (origin can be prefixed by sign)
+func (t *JStructTokenizerImpl) literalToken(kind TokenizerKind, origin []byte, l int) error {
 	t.scType = kind
-	l := len(origin)
 	idx := t.sc.Index()
 	for i, b := range origin[1:] {
-		e := t.sc.Next()
+		e := t.next()
 		if b != t.sc.Current() || e != nil {
 			t.v = t.sc.Bytes()
 			t.scType = KindUnknown
 			return InvalidJsonPtrError{Pos: idx + i + 1, Err: e}
 		}
 	}
-	idx = t.sc.Index()
-	// Check if it is a valid token ended by whitespaces
+	// Check if it is a valid token ended by whitespaces or delimiter
 	e := t.nextKeepWhiteSpace()
-	ch := t.sc.Current()
-	if t.scLevel == LevelRoot && (idx == t.sc.Index() || h.SpaceCh[ch]) {
-		t.v = t.sc.Bytes()[:l]
-		return nil
-	}
-	if t.scLevel != LevelRoot {
-		if ch == h.CommaCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValue
-			return nil
-		}
-		if ch == h.CloseBracketCh {
-			t.v = t.sc.Bytes()[:l]
-			t.scLevel = LevelValueLast
-			return nil
-		}
-	}
-
-	t.scType = KindUnknown
-	t.v = t.sc.Bytes()
-	return InvalidJsonPtrError{Pos: t.sc.Index(), Err: e}
+	return t.readValueEnd(t.sc.Current(), l, e)
 }
 
 func (t *JStructTokenizerImpl) readHex(n int) error {
 	var ch byte
 	for i := 0; i < n; i++ {
-		e := t.sc.Next()
+		e := t.next()
 		ch = t.sc.Current()
 		if e != nil {
 			return e
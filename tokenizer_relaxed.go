@@ -0,0 +1,143 @@
+package JsonStruct
+
+import (
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+)
+
+// Relaxed (JSON5 / JSONC) extensions of JStructTokenizerImpl, enabled by ParseOptions.Relaxed
+
+// readRelaxedValue reads value started by ch not allowed in strict mode
+func (t *JStructTokenizerImpl) readRelaxedValue(ch byte) error {
+	switch ch {
+	case h.SingleQuoteCh:
+		return t.readString(ch)
+	case h.PlusCh:
+		return t.ReadNumber(true)
+	case h.PointCh:
+		t.scType = KindFloatNumber
+		t.first = t.sc.Index()
+		return t.readFraction(t.first, false)
+	case 'I':
+		return t.hardcodedToken(KindFloatNumber, h.InfinityTokenData)
+	case 'N':
+		return t.hardcodedToken(KindFloatNumber, h.NaNTokenData)
+	}
+	return t.unexpectedToken()
+}
+
+// readSignedLiteral reads Infinity or NaN prefixed by sign, current byte is ch after sign
+func (t *JStructTokenizerImpl) readSignedLiteral(ch byte) error {
+	origin := h.InfinityTokenData
+	if ch == 'N' {
+		origin = h.NaNTokenData
+	}
+	t.first = -1
+	return t.literalToken(KindFloatNumber, origin, len(origin)+1)
+}
+
+// readHexNumber reads hexadecimal integer, current byte is 'x'
+func (t *JStructTokenizerImpl) readHexNumber(firstIdx int) error {
+	e := t.next()
+	ch := t.sc.Current()
+	if e != nil || !h.HexCh[ch] {
+		return t.invalidValue(e)
+	}
+	for {
+		e = t.next()
+		ch = t.sc.Current()
+		if !h.HexCh[ch] || e != nil {
+			break
+		}
+	}
+	l := t.sc.Index() - firstIdx
+	if e == io.EOF && h.HexCh[ch] {
+		return t.readValueEnd(ch, l+1, e)
+	}
+	if e != nil {
+		return t.invalidValue(e)
+	}
+	return t.readValueEnd(ch, l, e)
+}
+
+// readRelaxedKey reads single quoted or unquoted key
+func (t *JStructTokenizerImpl) readRelaxedKey(ch byte) error {
+	if ch == h.SingleQuoteCh {
+		return t.readKey(ch)
+	}
+	if !h.IdentCh[ch] || h.NumCh[ch] {
+		return t.unexpectedToken()
+	}
+	t.scType = KindString
+	first := t.sc.Index()
+	t.first = first
+	var e error
+	for {
+		e = t.next()
+		ch = t.sc.Current()
+		if e != nil || !h.IdentCh[ch] {
+			break
+		}
+	}
+	t.first = -1
+	t.scType = KindLiteral
+	l := t.sc.Index() - first
+	if e == nil {
+		ch, e = t.keepWhiteSpaceFrom(ch)
+	}
+	if e != nil || ch != h.ColonCh {
+		return t.invalidValue(e)
+	}
+	t.v = t.sc.Bytes()[:l]
+	t.scLevel = LevelKey
+	return nil
+}
+
+// readRelaxedEscape validates escape sequence of JSON5 string, ch follows backslash
+func (t *JStructTokenizerImpl) readRelaxedEscape(ch byte) error {
+	switch {
+	case ch == 'x':
+		return t.readHex(2)
+	case ch >= '1' && ch <= '9', ch == h.NewLineCh, ch == h.CarriageReturnCh:
+		return InvalidEscapeCharacterError
+	}
+	// \' \v \0 and any other character escaping itself
+	return nil
+}
+
+// skipComment skips `// ...` or `/* ... */` comment, current byte is '/'
+func (t *JStructTokenizerImpl) skipComment() error {
+	e := t.next()
+	if e != nil {
+		if e == io.EOF {
+			return InvalidCharacterError
+		}
+		return e
+	}
+	switch t.sc.Current() {
+	case h.SlashCh:
+		for {
+			e = t.next()
+			if e != nil || t.sc.Current() == h.NewLineCh {
+				return e
+			}
+		}
+	case h.AsteriskCh:
+		star := false
+		for {
+			e = t.next()
+			if e == io.EOF {
+				return UnterminatedCommentError
+			}
+			if e != nil {
+				return e
+			}
+			ch := t.sc.Current()
+			if star && ch == h.SlashCh {
+				return nil
+			}
+			star = ch == h.AsteriskCh
+		}
+	}
+	return InvalidCharacterError
+}
@@ -0,0 +1,228 @@
+package JsonStruct
+
+import (
+	"bytes"
+	h "github.com/Pencroff/JsonStruct/helper"
+	"io"
+)
+
+// Valid reports whether b is well-formed JSON by the same grammar as encoding/json Valid, nesting depth is not limited.
+// Input checked in single pass without tokenizer and decoding of values.
+func Valid(b []byte) bool {
+	var buf [32]byte
+	v := validator{b: b, stack: buf[:0]}
+	return v.valid()
+}
+
+// ValidReader checks whether data of rd is well-formed JSON, returns the same error as JStructParse
+func ValidReader(rd io.Reader) error {
+	tc := acquireTokenizer(rd, ParseOptions{NoTimeDetection: true})
+	e := validTokens(tc)
+	releaseTokenizer(tc)
+	return e
+}
+
+// validTokens reads tokens till the end of data, root value should be completed same as by parseTokens
+func validTokens(tc *JStructTokenizerImpl) error {
+	depth := 0
+	done := false
+	for {
+		e := tc.Next()
+		if e == io.EOF && done {
+			return nil
+		}
+		if e != nil {
+			return e
+		}
+		switch tc.Level() {
+		case LevelKey:
+			continue
+		case LevelArray, LevelObject:
+			depth++
+			continue
+		case LevelArrayEnd, LevelObjectEnd, LevelValueLast:
+			depth--
+		}
+		done = depth == 0
+	}
+}
+
+// validator checks JSON grammar of b, stack keeps closing brackets of open containers,
+// nesting handled without recursion
+type validator struct {
+	b     []byte
+	i     int
+	stack []byte
+}
+
+func (v *validator) valid() bool {
+	if !v.value() {
+		return false
+	}
+	for {
+		v.skipSpace()
+		l := len(v.stack)
+		if l == 0 {
+			return v.i == len(v.b)
+		}
+		if v.i == len(v.b) {
+			return false
+		}
+		ch := v.b[v.i]
+		v.i++
+		switch {
+		case ch == h.CommaCh:
+			if v.stack[l-1] == h.CloseBraceCh && !v.key() {
+				return false
+			}
+			if !v.value() {
+				return false
+			}
+		case ch == v.stack[l-1]:
+			v.stack = v.stack[:l-1]
+		default:
+			return false
+		}
+	}
+}
+
+// value checks the next value, opened container pushed to stack with key of its first member
+func (v *validator) value() bool {
+	for {
+		v.skipSpace()
+		if v.i == len(v.b) {
+			return false
+		}
+		switch ch := v.b[v.i]; ch {
+		case h.OpenBraceCh, h.OpenBracketCh:
+			v.i++
+			v.skipSpace()
+			// '}' and ']' follow '{' and '[' after one character
+			end := ch + 2
+			if v.i < len(v.b) && v.b[v.i] == end {
+				v.i++
+				return true
+			}
+			v.stack = append(v.stack, end)
+			if ch == h.OpenBraceCh && !v.key() {
+				return false
+			}
+		case h.QuoteCh:
+			return v.str()
+		case 't':
+			return v.literal(h.TrueTokenData)
+		case 'f':
+			return v.literal(h.FalseTokenData)
+		case 'n':
+			return v.literal(h.NullTokenData)
+		default:
+			return v.number()
+		}
+	}
+}
+
+// key checks object key with colon after it
+func (v *validator) key() bool {
+	v.skipSpace()
+	if v.i == len(v.b) || v.b[v.i] != h.QuoteCh || !v.str() {
+		return false
+	}
+	v.skipSpace()
+	if v.i == len(v.b) || v.b[v.i] != h.ColonCh {
+		return false
+	}
+	v.i++
+	return true
+}
+
+func (v *validator) skipSpace() {
+	b := v.b
+	i := v.i
+	for i < len(b) && h.SpaceCh[b[i]] {
+		i++
+	}
+	v.i = i
+}
+
+func (v *validator) literal(lit []byte) bool {
+	if !bytes.HasPrefix(v.b[v.i:], lit) {
+		return false
+	}
+	v.i += len(lit)
+	return true
+}
+
+// str checks string started at quote
+func (v *validator) str() bool {
+	b := v.b
+	l := len(b)
+	for i := v.i + 1; i < l; i++ {
+		ch := b[i]
+		switch {
+		case ch == h.QuoteCh:
+			v.i = i + 1
+			return true
+		case ch < 0x20:
+			return false
+		case ch == h.BackSlashCh:
+			i++
+			if i == l {
+				return false
+			}
+			switch b[i] {
+			case h.QuoteCh, h.BackSlashCh, '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				if i+4 >= l {
+					return false
+				}
+				for _, c := range b[i+1 : i+5] {
+					if !h.HexCh[c] {
+						return false
+					}
+				}
+				i += 4
+			default:
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// number checks number by grammar: -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+func (v *validator) number() bool {
+	b := v.b
+	l := len(b)
+	i := v.i
+	if b[i] == h.MinusCh {
+		i++
+	}
+	switch {
+	case i < l && b[i] == '0':
+		i++
+	case i < l && b[i] >= '1' && b[i] <= '9':
+		i = skipDigits(b, i)
+	default:
+		return false
+	}
+	if i < l && b[i] == h.PointCh {
+		j := skipDigits(b, i+1)
+		if j == i+1 {
+			return false
+		}
+		i = j
+	}
+	if i < l && (b[i] == h.ExpSmCh || b[i] == h.ExpCh) {
+		i++
+		if i < l && (b[i] == h.PlusCh || b[i] == h.MinusCh) {
+			i++
+		}
+		j := skipDigits(b, i)
+		if j == i {
+			return false
+		}
+		i = j
+	}
+	v.i = i
+	return true
+}